@@ -1,24 +1,20 @@
 package main
 
 import (
-	"bytes"
 	"flag"
 	"fmt"
-	"image/color"
 	"image/png"
-	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
 
-	gpl "github.com/gravestench/gpl/pkg"
-
 	dc6lib "github.com/gravestench/dc6/pkg"
 )
 
 type options struct {
 	dc6Path *string
 	palPath *string
+	palDir  *string
 	pngPath *string
 }
 
@@ -44,20 +40,21 @@ func main() {
 		return
 	}
 
-	if *o.palPath != "" {
-		palData, err := ioutil.ReadFile(*o.palPath)
-		if err != nil {
+	if *o.palDir != "" {
+		if err := dc6lib.Palettes.LoadDir(*o.palDir); err != nil {
 			fmt.Println(err)
 			return
 		}
+	}
 
-		gplInstance, err := gpl.Decode(bytes.NewBuffer(palData))
+	if *o.palPath != "" {
+		p, err := dc6lib.Palettes.Resolve(*o.palPath)
 		if err != nil {
-			fmt.Println("palette is not a GIMP palette file...")
+			fmt.Println(err)
 			return
 		}
 
-		dc6.SetPalette(color.Palette(gplInstance))
+		dc6.SetPalette(p)
 	}
 
 	numDirections := len(dc6.Directions)
@@ -83,7 +80,7 @@ func main() {
 				log.Fatal(err)
 			}
 
-			if err := png.Encode(f, dc6.Directions[dirIdx].Frames[frameIdx]); err != nil {
+			if err := png.Encode(f, dc6.Directions[dirIdx].Frames[frameIdx].ToPaletted()); err != nil {
 				_ = f.Close()
 				log.Fatal(err)
 			}
@@ -97,7 +94,8 @@ func main() {
 
 func parseOptions(o *options) (terminate bool) {
 	o.dc6Path = flag.String("dc6", "", "input dc6 file (required)")
-	o.palPath = flag.String("pal", "", "input pal file (optional)")
+	o.palPath = flag.String("pal", "", "input palette file, or the name of a palette in -paldir (optional)")
+	o.palDir = flag.String("paldir", "", "directory of palettes -pal can name, like data/global/palette of the game (optional)")
 	o.pngPath = flag.String("png", "", "path to png file (optional)")
 
 	flag.Parse()
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	dc6lib "github.com/gravestench/dc6/pkg"
+	"github.com/gravestench/dc6/pkg/spritesheet"
+)
+
+type options struct {
+	pngPath *string
+	palPath *string
+	palDir  *string
+	dc6Path *string
+	dither  *string
+	trim    *bool
+	origin  *string
+	grid    *string
+	json    *string
+}
+
+var ditherModes = map[string]dc6lib.Dither{
+	"none":            dc6lib.DitherNone,
+	"ordered":         dc6lib.DitherOrdered,
+	"floyd-steinberg": dc6lib.DitherFloydSteinberg,
+}
+
+func main() {
+	var o options
+
+	if parseOptions(&o) {
+		flag.Usage()
+		return
+	}
+
+	dither, found := ditherModes[*o.dither]
+	if !found {
+		fmt.Printf("unknown dither mode %q\n", *o.dither)
+		return
+	}
+
+	importOptions := dc6lib.ImportOptions{Dither: dither, Trim: *o.trim}
+
+	if _, err := fmt.Sscanf(*o.origin, "%d,%d", &importOptions.Origin.X, &importOptions.Origin.Y); err != nil {
+		fmt.Printf("origin %q is not of the form x,y\n", *o.origin)
+		return
+	}
+
+	if *o.palDir != "" {
+		if err := dc6lib.Palettes.LoadDir(*o.palDir); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	if *o.palPath != "" {
+		p, err := dc6lib.Palettes.Resolve(*o.palPath)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		importOptions.Palette = p
+	}
+
+	images, err := readImages(*o.pngPath, *o.grid, *o.json)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	dc6, err := importOptions.Import(images)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	data, err := dc6.ToBytes()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err := os.WriteFile(*o.dc6Path, data, 0o644); err != nil {
+		fmt.Println(err)
+		return
+	}
+}
+
+// readImages reads the png file at the given path, or if it does not exist,
+// the png files named like the ones written by dc6-convert for a DC6 with
+// multiple frames: <name>_d<direction>_f<frame>.png
+//
+// If a grid or a json frame map is given, the png file is a sprite sheet that
+// is sliced into frames.
+func readImages(pngPath, grid, jsonPath string) ([][]image.Image, error) {
+	if grid != "" || jsonPath != "" {
+		sheet, err := readImage(pngPath)
+		if err != nil {
+			return nil, err
+		}
+
+		return sliceSheet(sheet, grid, jsonPath)
+	}
+
+	if _, err := os.Stat(pngPath); err == nil {
+		img, err := readImage(pngPath)
+		if err != nil {
+			return nil, err
+		}
+
+		return [][]image.Image{{img}}, nil
+	}
+
+	noExt := fileNameWithoutExt(pngPath)
+
+	var images [][]image.Image
+
+	for dirIdx := 0; ; dirIdx++ {
+		var frames []image.Image
+
+		for frameIdx := 0; ; frameIdx++ {
+			framePath := fmt.Sprintf("%s_d%v_f%v.png", noExt, dirIdx, frameIdx)
+			if _, err := os.Stat(framePath); err != nil {
+				break
+			}
+
+			img, err := readImage(framePath)
+			if err != nil {
+				return nil, err
+			}
+
+			frames = append(frames, img)
+		}
+
+		if len(frames) == 0 {
+			break
+		}
+
+		images = append(images, frames)
+	}
+
+	if len(images) == 0 {
+		return nil, fmt.Errorf("could not find %s or %s_d0_f0.png", pngPath, noExt)
+	}
+
+	return images, nil
+}
+
+func sliceSheet(sheet image.Image, grid, jsonPath string) ([][]image.Image, error) {
+	if jsonPath == "" {
+		g := spritesheet.Grid{}
+
+		if _, err := fmt.Sscanf(grid, "%dx%d", &g.CellWidth, &g.CellHeight); err != nil {
+			return nil, fmt.Errorf("grid %q is not of the form <width>x<height>", grid)
+		}
+
+		return g.Slice(sheet)
+	}
+
+	f, err := os.Open(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	frameMap, err := spritesheet.ReadFrameMap(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return frameMap.Slice(sheet)
+}
+
+func readImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode %s, %w", path, err)
+	}
+
+	return img, nil
+}
+
+func parseOptions(o *options) (terminate bool) {
+	o.pngPath = flag.String("png", "", "input png file, or the name the png files of each frame are derived from (required)")
+	o.palPath = flag.String("pal", "", "input palette file, or the name of a palette in -paldir (optional)")
+	o.palDir = flag.String("paldir", "", "directory of palettes -pal can name, like data/global/palette of the game (optional)")
+	o.dc6Path = flag.String("dc6", "", "output dc6 file (required)")
+	o.dither = flag.String("dither", "none", "dither mode: none, ordered or floyd-steinberg")
+	o.trim = flag.Bool("trim", false, "crop transparent edges of each frame")
+	o.origin = flag.String("origin", "0,0", "location in the png files the frame offsets are relative to, as x,y")
+	o.grid = flag.String("grid", "", "slice the png file as a sprite sheet with cells of <width>x<height> (optional)")
+	o.json = flag.String("json", "", "slice the png file as a sprite sheet with a TexturePacker or Aseprite json file (optional)")
+
+	flag.Parse()
+
+	if *o.pngPath == "" || *o.dc6Path == "" {
+		flag.Usage()
+		return true
+	}
+
+	return false
+}
+
+func fileNameWithoutExt(fileName string) string {
+	return fileName[:len(fileName)-len(filepath.Ext(fileName))]
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bytes"
 	"flag"
 	"fmt"
 	"image/color"
@@ -9,7 +8,6 @@ import (
 	"path"
 
 	"github.com/AllenDang/giu"
-	"github.com/gravestench/gpl"
 
 	"github.com/gravestench/dc6"
 	"github.com/gravestench/dc6/pkg/giuwidget"
@@ -41,26 +39,30 @@ func main() {
 		return
 	}
 
-	dc6, err := dc6.FromBytes(fileContents)
-	if err != nil {
-		fmt.Print(err)
-		return
+	if *o.palDir != "" {
+		if err := dc6.Palettes.LoadDir(*o.palDir); err != nil {
+			fmt.Println(err)
+			return
+		}
 	}
 
+	var pal color.Palette
+
 	if *o.palPath != "" {
-		palData, err := ioutil.ReadFile(*o.palPath)
-		if err != nil {
+		if pal, err = dc6.Palettes.Resolve(*o.palPath); err != nil {
 			fmt.Println(err)
 			return
 		}
+	}
 
-		gplInstance, err := gpl.Decode(bytes.NewBuffer(palData))
-		if err != nil {
-			fmt.Println("palette is not a GIMP palette file...")
-			return
-		}
+	dc6, err := dc6.FromBytes(fileContents)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
 
-		dc6.SetPalette(color.Palette(gplInstance))
+	if pal != nil {
+		dc6.SetPalette(pal)
 	}
 
 	f0 := dc6.Directions[0].Frames[0]
@@ -94,13 +96,15 @@ func main() {
 type options struct {
 	dc6Path *string
 	palPath *string
+	palDir  *string
 	pngPath *string
 	scale   *float64
 }
 
 func parseOptions(o *options) (terminate bool) {
 	o.dc6Path = flag.String("dc6", "", "input dc6 file (required)")
-	o.palPath = flag.String("pal", "", "input pal file (optional)")
+	o.palPath = flag.String("pal", "", "input palette file, or the name of a palette in -paldir (optional)")
+	o.palDir = flag.String("paldir", "", "directory of palettes -pal can name, like data/global/palette of the game (optional)")
 	o.pngPath = flag.String("png", "", "path to png file (optional)")
 	o.scale = flag.Float64("scale", 1.0, "scale")
 
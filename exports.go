@@ -1,6 +1,9 @@
 package dc6
 
 import (
+	"image"
+	"io"
+
 	"github.com/gravestench/dc6/pkg"
 )
 
@@ -12,8 +15,81 @@ type (
 	Direction   = pkg.Direction
 	Frame       = pkg.Frame
 	FrameHeader = pkg.FrameHeader
+
+	DecodeOptions = pkg.DecodeOptions
+	DecodeMode    = pkg.DecodeMode
+	DecodeError   = pkg.DecodeError
+	Warning       = pkg.Warning
+	WarningKind   = pkg.WarningKind
+	Difference    = pkg.Difference
+	ImportOptions = pkg.ImportOptions
+	Dither        = pkg.Dither
+	RemappedFrame = pkg.RemappedFrame
+	RetargetStats = pkg.RetargetStats
+)
+
+const (
+	ModeDefault = pkg.ModeDefault
+	ModeStrict  = pkg.ModeStrict
+	ModeLenient = pkg.ModeLenient
+
+	WarningNextBlockMismatch    = pkg.WarningNextBlockMismatch
+	WarningBadTermination       = pkg.WarningBadTermination
+	WarningTrailingData         = pkg.WarningTrailingData
+	WarningScanlineOverrun      = pkg.WarningScanlineOverrun
+	WarningBadVersion           = pkg.WarningBadVersion
+	WarningUnterminatedScanline = pkg.WarningUnterminatedScanline
+
+	DitherNone           = pkg.DitherNone
+	DitherOrdered        = pkg.DitherOrdered
+	DitherFloydSteinberg = pkg.DitherFloydSteinberg
+
+	DefaultAlphaThreshold = pkg.DefaultAlphaThreshold
+)
+
+var (
+	ErrTruncatedHeader    = pkg.ErrTruncatedHeader
+	ErrBadVersion         = pkg.ErrBadVersion
+	ErrTruncatedFrameData = pkg.ErrTruncatedFrameData
+	ErrPointerOutOfRange  = pkg.ErrPointerOutOfRange
+	ErrOversizedFrame     = pkg.ErrOversizedFrame
+	ErrBadTerminator      = pkg.ErrBadTerminator
+	ErrLimitExceeded      = pkg.ErrLimitExceeded
+	ErrNextBlockMismatch  = pkg.ErrNextBlockMismatch
+	ErrTrailingData       = pkg.ErrTrailingData
+	ErrScanlineOverrun    = pkg.ErrScanlineOverrun
 )
 
+var Palettes = pkg.Palettes
+
 func FromBytes(data []byte) (result *DC6, err error) {
 	return pkg.FromBytes(data)
 }
+
+func Decode(r io.Reader) (result *DC6, err error) {
+	return pkg.Decode(r)
+}
+
+func Open(r io.ReaderAt, size int64) (result *DC6, err error) {
+	return pkg.Open(r, size)
+}
+
+func ReadHeader(r io.Reader) (*Header, error) {
+	return pkg.ReadHeader(r)
+}
+
+func ReadFrameHeaders(r io.ReaderAt, size int64) ([]FrameHeader, error) {
+	return pkg.ReadFrameHeaders(r, size)
+}
+
+func DecodeFrame(r io.Reader, direction, frame int) (*Frame, error) {
+	return pkg.DecodeFrame(r, direction, frame)
+}
+
+func New(directions, framesPerDirection int) *DC6 {
+	return pkg.New(directions, framesPerDirection)
+}
+
+func NewFrameFromPaletted(img *image.Paletted, offsetX, offsetY int) *Frame {
+	return pkg.NewFrameFromPaletted(img, offsetX, offsetY)
+}
@@ -0,0 +1,131 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// defaultFlags is the value of the flags in the file header of the DC6 files
+// of the game.
+const defaultFlags = 1
+
+// New creates an empty DC6 with the given number of directions. Frames are
+// added to each direction with Direction.AddFrame, framesPerDirection is the
+// number of frames each direction is expected to get.
+func New(directions, framesPerDirection int) *DC6 {
+	d := &DC6{
+		Version:     dc6Version,
+		Flags:       defaultFlags,
+		Termination: terminationBytes(nil, 4),
+		Directions:  make([]*Direction, directions),
+	}
+
+	for idx := range d.Directions {
+		d.Directions[idx] = &Direction{dc6: d, Frames: make([]*Frame, 0, framesPerDirection)}
+	}
+
+	return d
+}
+
+// AddFrame appends a frame to the direction, the frame uses the palette of the
+// DC6 the direction belongs to.
+func (d *Direction) AddFrame(frame *Frame) {
+	frame.dc6 = d.dc6
+	d.Frames = append(d.Frames, frame)
+}
+
+// NewFrameFromPaletted creates a frame from the palette indices of the given
+// image, placed at the given offset. Pixels whose palette color is fully
+// transparent are transparent in the frame, all other pixels are opaque.
+//
+// Only the palette indices are used, the frame is drawn with the palette of
+// the DC6 it is added to.
+func NewFrameFromPaletted(img *image.Paletted, offsetX, offsetY int) *Frame {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	transparent := make([]bool, len(img.Palette))
+	for idx, c := range img.Palette {
+		_, _, _, a := c.RGBA()
+		transparent[idx] = a == 0
+	}
+
+	frame := &Frame{
+		Width:     uint32(width),
+		Height:    uint32(height),
+		OffsetX:   int32(offsetX),
+		OffsetY:   int32(offsetY),
+		IndexData: make([]byte, width*height),
+		Mask:      make([]bool, width*height),
+	}
+
+	for y := 0; y < height; y++ {
+		row := img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y+y):]
+
+		for x := 0; x < width; x++ {
+			cidx := row[x]
+			if int(cidx) < len(transparent) && transparent[cidx] {
+				continue
+			}
+
+			frame.IndexData[y*width+x] = cidx
+			frame.Mask[y*width+x] = true
+		}
+	}
+
+	return frame
+}
+
+// Validate checks that the DC6 can be encoded: it must have at least one
+// direction, every direction must have the same number of frames, and the
+// IndexData and Mask of every frame must match its size.
+func (d *DC6) Validate() error {
+	if len(d.Directions) == 0 {
+		return errors.New("no directions")
+	}
+
+	for dirIdx, direction := range d.Directions {
+		if direction == nil {
+			return fmt.Errorf("direction %d is nil", dirIdx)
+		}
+
+		if numFrames, expected := len(direction.Frames), len(d.Directions[0].Frames); numFrames != expected {
+			return fmt.Errorf("direction %d has %d frames, expected %d", dirIdx, numFrames, expected)
+		}
+
+		for frameIdx, frame := range direction.Frames {
+			if frame == nil {
+				return fmt.Errorf("direction %d frame %d is nil", dirIdx, frameIdx)
+			}
+
+			if err := frame.validate(); err != nil {
+				return fmt.Errorf("direction %d frame %d, %w", dirIdx, frameIdx, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate checks that the IndexData and Mask of the frame match its size.
+// Frames that have not been decoded yet are not checked.
+func (f *Frame) validate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pixels := uint64(f.Width) * uint64(f.Height)
+	if pixels > maxFramePixels {
+		return fmt.Errorf("%w, %dx%d pixels", ErrOversizedFrame, f.Width, f.Height)
+	}
+
+	if f.IndexData != nil && uint64(len(f.IndexData)) != pixels {
+		return fmt.Errorf("index data has %d pixels, expected %d", len(f.IndexData), pixels)
+	}
+
+	if f.Mask != nil && uint64(len(f.Mask)) != pixels {
+		return fmt.Errorf("mask has %d pixels, expected %d", len(f.Mask), pixels)
+	}
+
+	return nil
+}
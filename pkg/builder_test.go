@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	d := New(2, 3)
+
+	if d.Version != dc6Version || d.Flags != defaultFlags || !isTermination(d.Termination) || len(d.Termination) != 4 {
+		t.Errorf("unexpected header %d %d % x", d.Version, d.Flags, d.Termination)
+	}
+
+	if len(d.Directions) != 2 || len(d.Directions[0].Frames) != 0 || cap(d.Directions[0].Frames) != 3 {
+		t.Fatalf("unexpected directions %v", d.Directions)
+	}
+
+	p := color.Palette{color.Transparent, color.RGBA{R: 0xff, A: 0xff}}
+	d.SetPalette(p)
+
+	frame := &Frame{Width: 1, Height: 1, IndexData: []byte{1}}
+	d.Directions[1].AddFrame(frame)
+
+	if got := frame.At(0, 0); got != p[1] {
+		t.Errorf("added frame is not drawn with the palette of the DC6, got %v", got)
+	}
+}
+
+func TestNewFrameFromPaletted(t *testing.T) {
+	p := color.Palette{
+		color.RGBA{R: 1, A: 0xff}, // index 0 is opaque in the image
+		color.RGBA{G: 2, A: 0xff},
+		color.RGBA{}, // transparent
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, 4, 3), p)
+	img.Pix = []byte{
+		0, 1, 2, 1,
+		2, 2, 2, 2,
+		1, 0, 1, 3, // 3 is not in the palette
+	}
+
+	// a sub-image with bounds that do not start at the origin
+	sub := img.SubImage(image.Rect(1, 1, 4, 3)).(*image.Paletted)
+	frame := NewFrameFromPaletted(sub, -2, 5)
+
+	if frame.Width != 3 || frame.Height != 2 || frame.OffsetX != -2 || frame.OffsetY != 5 {
+		t.Fatalf("unexpected frame header %dx%d at %d,%d", frame.Width, frame.Height, frame.OffsetX, frame.OffsetY)
+	}
+
+	wantIndices := []byte{0, 0, 0, 0, 1, 3}
+	wantMask := []bool{false, false, false, true, true, true}
+
+	for idx := range wantIndices {
+		if frame.IndexData[idx] != wantIndices[idx] || frame.Mask[idx] != wantMask[idx] {
+			t.Errorf("pixel %d is %d %v, expected %d %v",
+				idx, frame.IndexData[idx], frame.Mask[idx], wantIndices[idx], wantMask[idx])
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := newTestDC6(2, 2).Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]func(d *DC6){
+		"no directions":     func(d *DC6) { d.Directions = nil },
+		"nil direction":     func(d *DC6) { d.Directions[1] = nil },
+		"nil frame":         func(d *DC6) { d.Directions[1].Frames[0] = nil },
+		"frame count":       func(d *DC6) { d.Directions[1].Frames = d.Directions[1].Frames[:1] },
+		"index data length": func(d *DC6) { d.Directions[0].Frames[1].IndexData = []byte{1} },
+		"mask length":       func(d *DC6) { d.Directions[0].Frames[1].Mask = []bool{true} },
+	}
+
+	for name, modify := range tests {
+		d := newTestDC6(2, 2)
+		modify(d)
+
+		if err := d.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+
+		if _, err := d.ToBytes(); err == nil {
+			t.Errorf("%s: expected an encoding error", name)
+		}
+	}
+}
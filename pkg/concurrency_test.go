@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"image"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentReads uses a DC6 from multiple goroutines at the same time, it
+// is meant to be run with the race detector (go test -race).
+func TestConcurrentReads(t *testing.T) {
+	data, err := newTestDC6(2, 3).ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, decode := range decoders(DecodeOptions{}) {
+		d, err := decode(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		const numGoroutines = 8
+
+		var wg sync.WaitGroup
+
+		errs := make(chan error, numGoroutines)
+
+		for i := 0; i < numGoroutines; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for dirIdx, direction := range d.Directions {
+					for frameIdx, frame := range direction.Frames {
+						bounds := frame.Bounds()
+						_ = frame.At(bounds.Min.X, bounds.Min.Y)
+						_ = frame.ToImageRGBA()
+						_ = frame.Warnings()
+
+						if _, err := d.ToPaletted(dirIdx, frameIdx); err != nil {
+							errs <- err
+							return
+						}
+
+						frame.DrawInto(image.NewRGBA(bounds))
+					}
+				}
+
+				_ = d.Warnings()
+
+				if _, err := d.ToBytes(); err != nil {
+					errs <- err
+					return
+				}
+
+				if clone := d.Clone(); !clone.Equal(d) {
+					t.Errorf("%s: clone differs: %v", name, clone.Diff(d))
+				}
+			}()
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
@@ -1,18 +1,29 @@
 package pkg
 
 import (
+	"bytes"
 	"fmt"
 	"image/color"
+	"io"
 	"math"
+	"sort"
+	"sync"
 
 	"github.com/gravestench/bitstream"
+
+	"github.com/gravestench/dc6/pkg/palette"
 )
 
+const dc6Version = 6
+
 const (
 	endOfScanLine = 0x80
 	maxRunLength  = 0x7f
 )
 
+// maxFramePixels is the largest number of pixels a frame may have.
+const maxFramePixels = math.MaxInt32
+
 type scanlineState int
 
 const (
@@ -23,173 +34,526 @@ const (
 
 // DC6 represents a DC6 file.
 type DC6 struct {
-	Version     int32
-	Flags       uint32
-	Encoding    uint32
-	Termination []byte // 4 bytes
-	Directions  []*Direction
-	palette     color.Palette
+	Version       int32
+	Flags         uint32
+	Encoding      uint32
+	Termination   []byte   // 4 bytes
+	FramePointers []uint32 // file offset of each frame header, as read from the file
+	Directions    []*Direction
+	palette       color.Palette
+	lut           *rgbaLUT // derived from the palette by SetPalette
+	warnings      []Warning
+	mode          DecodeMode
+	salvaging     bool
+	source        io.ReaderAt   // the file the DC6 was opened from, if any
+	gaps          map[int64]gap // the parts of the file outside of the frame blocks, by offset
 }
 
 type Direction struct {
+	dc6    *DC6
 	Frames []*Frame // size is Directions*FramesPerDirection
 }
 
-// FromBytes uses restruct to read the binary dc6 data into structs then parses image data from the frame data.
+// FromBytes decodes the given DC6 file data.
 func FromBytes(data []byte) (result *DC6, err error) {
-	result = &DC6{}
+	return DecodeOptions{}.FromBytes(data)
+}
+
+// Decode reads a DC6 from the given reader, see DecodeOptions.Decode.
+func Decode(r io.Reader) (result *DC6, err error) {
+	return DecodeOptions{}.Decode(r)
+}
+
+// decodeHeader decodes the file header and frame pointer table, yielding the
+// number of directions and frames per direction.
+func (d *DC6) decodeHeader(r io.Reader, limits *limiter) (numDirections, framesPerDirection int, err error) {
+	const (
+		terminationOffset = 12
+		directionsOffset  = 16
+	)
+
+	header, err := ReadHeader(r)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	d.Version = header.Version
+	d.Flags = header.Flags
+	d.Encoding = header.Encoding
+	d.Termination = header.Termination
+	dirs, frames := uint32(header.Directions), uint32(header.FramesPerDirection)
+
+	if d.Version != dc6Version {
+		err = fmt.Errorf("%w %d, expected %d", ErrBadVersion, d.Version, dc6Version)
+		if err = d.anomaly(WarningBadVersion, 0, err); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if !isTermination(d.Termination) {
+		err = fmt.Errorf("%w, termination bytes are % x", ErrBadTerminator, d.Termination)
+		if err = d.anomaly(WarningBadTermination, terminationOffset, err); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if err = limits.checkHeader(dirs, frames); err != nil {
+		return 0, 0, headerError(directionsOffset, err)
+	}
+
+	numDirections, framesPerDirection = int(dirs), int(frames)
+
+	d.Directions = make([]*Direction, numDirections)
+	for idx := range d.Directions {
+		d.Directions[idx] = &Direction{dc6: d, Frames: make([]*Frame, framesPerDirection)}
+	}
+
+	if err = d.decodeFramePointers(r, numDirections*framesPerDirection); err != nil {
+		return 0, 0, err
+	}
+
+	return numDirections, framesPerDirection, nil
+}
+
+func (d *DC6) decodeFramePointers(r io.Reader, totalFrames int) error {
+	data := make([]byte, framePointerSize*totalFrames)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return headerError(headerSize, wrapErr(ErrTruncatedHeader, err))
+	}
+
+	bitstreamMu.Lock()
+	defer bitstreamMu.Unlock()
 
 	stream := bitstream.NewReader().FromBytes(data...)
 
-	if err = result.decodeHeader(stream); err != nil {
-		return nil, err
+	d.FramePointers = make([]uint32, totalFrames)
+	for idx := range d.FramePointers {
+		d.FramePointers[idx], _ = stream.Next(framePointerSize).Bytes().AsUInt32()
 	}
 
-	if err = result.decodeBody(stream); err != nil {
-		return nil, err
+	return nil
+}
+
+// decodeBody reads the frames from a sequential reader, positioned right after
+// the frame pointer table.
+func (d *DC6) decodeBody(r io.Reader, totalFrames int, limits *limiter) (err error) {
+	// frames are read in the order they appear in the file
+	order := d.fileOrder()
+
+	offset := int64(headerSize + framePointerSize*totalFrames)
+
+	// once the end of the stream is reached, the remaining frames are missing
+	var truncated error
+
+	for orderIdx, idx := range order {
+		pointer := int64(d.FramePointers[idx])
+
+		if truncated != nil {
+			if err = d.salvage(idx, nil, d.frameError(idx, pointer, truncated)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if pointer < offset {
+			// frames sharing a frame pointer share the frame block
+			if orderIdx > 0 && d.FramePointers[order[orderIdx-1]] == d.FramePointers[idx] {
+				d.setFrame(idx, d.frameByIndex(order[orderIdx-1]).clone(d))
+				continue
+			}
+
+			const fmtErr = "%w, frame at %d overlaps the data before it"
+			err = d.frameError(idx, pointerOffset(idx), fmt.Errorf(fmtErr, ErrPointerOutOfRange, pointer))
+
+			if err = d.salvage(idx, nil, err); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err = limits.consume(uint64(pointer - offset)); err != nil {
+			if err = d.salvage(idx, nil, d.frameError(idx, pointerOffset(idx), err)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		// padding is kept, so that the file can be encoded as it was
+		padding := &bytes.Buffer{}
+
+		if _, err = io.CopyN(padding, r, pointer-offset); err != nil {
+			truncated = wrapErr(ErrPointerOutOfRange, err)
+
+			if err = d.salvage(idx, nil, d.frameError(idx, pointerOffset(idx), truncated)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if padding.Len() > 0 {
+			d.addGap(gap{offset: offset, length: int64(padding.Len()), data: padding.Bytes()})
+		}
+
+		frame, err := d.decodeFrameHeader(r)
+		if err != nil {
+			truncated = wrapErr(ErrTruncatedFrameData, err)
+
+			if err = d.salvage(idx, nil, d.frameError(idx, pointer, truncated)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		offset = pointer + frameHeaderSize
+
+		if err = d.checkFrameHeader(idx, pointer, frame, limits); err != nil {
+			if err = d.salvage(idx, nil, err); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		frame.dataOffset = pointer + frameHeaderSize
+		frame.FrameData = make([]byte, frame.Length)
+		frame.Terminator = make([]byte, terminatorSize)
+
+		if err = readFrameData(r, frame); err != nil {
+			truncated = wrapErr(ErrTruncatedFrameData, err)
+
+			// the frame keeps whatever frame data could be read
+			if err = d.salvage(idx, frame, d.frameError(idx, frame.dataOffset, truncated)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		offset = frame.dataOffset + int64(frame.Length) + terminatorSize
+
+		d.setFrame(idx, frame)
+	}
+
+	if truncated != nil {
+		return nil
 	}
 
-	return result, nil
+	// the data after the last frame is not read, a single byte tells whether
+	// there is any
+	if n, _ := io.ReadFull(r, make([]byte, 1)); n > 0 {
+		err = fmt.Errorf("%w after the last frame", ErrTrailingData)
+		if err = d.anomaly(WarningTrailingData, offset, err); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (d *DC6) decodeHeader(stream *bitstream.Reader) (err error) {
-	const (
-		versionBytes     = 4
-		flagsBytes       = 4
-		encodingBytes    = 4
-		terminationBytes = 4
-	)
+// readFrameData reads the frame data and terminator of the frame, truncating
+// them to what could be read if the end of the stream is reached.
+func readFrameData(r io.Reader, frame *Frame) error {
+	n, err := io.ReadFull(r, frame.FrameData)
+	if err != nil {
+		frame.FrameData, frame.Terminator = frame.FrameData[:n], nil
+		return err
+	}
 
-	// only check last err
-	d.Version, _ = stream.Next(versionBytes).Bytes().AsInt32()
-	d.Flags, _ = stream.Next(flagsBytes).Bytes().AsUInt32()
-	d.Encoding, _ = stream.Next(encodingBytes).Bytes().AsUInt32()
-	d.Termination, err = stream.Next(terminationBytes).Bytes().AsBytes()
+	n, err = io.ReadFull(r, frame.Terminator)
+	if err != nil {
+		frame.Terminator = frame.Terminator[:n]
+		return err
+	}
 
-	return err
+	return nil
 }
 
-func (d *DC6) decodeBody(stream *bitstream.Reader) (err error) {
+// checkFrameHeader validates the frame header of the frame at the given index
+// of the frame pointer table, before any memory is allocated for it.
+func (d *DC6) checkFrameHeader(idx int, pointer int64, frame *Frame, limits *limiter) error {
 	const (
-		terminatorSize          = 3
-		directionsBytes         = 4
-		framesPerDirectionBytes = 4
-		framePointerBytes       = 4
+		widthOffset  = 4
+		lengthOffset = 28
 	)
 
-	const (
-		frameFlippedBytes   = 4
-		frameWidthBytes     = 4
-		frameHeightBytes    = 4
-		frameOffsetXBytes   = 4
-		frameOffsetYBytes   = 4
-		frameUnknownBytes   = 4
-		frameNextBlockBytes = 4
-		frameLengthBytes    = 4
-	)
+	if uint64(frame.Width)*uint64(frame.Height) > maxFramePixels {
+		const fmtErr = "%w, %dx%d pixels"
+		return d.frameError(idx, pointer+widthOffset, fmt.Errorf(fmtErr, ErrOversizedFrame, frame.Width, frame.Height))
+	}
 
-	numDirections, _ := stream.Next(directionsBytes).Bytes().AsUInt32()
-	framesPerDirection, err := stream.Next(framesPerDirectionBytes).Bytes().AsUInt32()
-	totalFrames := int(numDirections * framesPerDirection)
+	if err := limits.checkFrame(frame.Width, frame.Height); err != nil {
+		return d.frameError(idx, pointer+widthOffset, err)
+	}
 
-	d.Directions = make([]*Direction, numDirections)
+	if err := limits.consume(frameHeaderSize + uint64(frame.Length) + terminatorSize); err != nil {
+		return d.frameError(idx, pointer+lengthOffset, err)
+	}
 
-	for i := 0; i < totalFrames; i++ {
-		if _, err = stream.Next(framePointerBytes).Bytes().AsUInt32(); err != nil {
-			return err
-		}
+	return nil
+}
+
+// pointerOffset returns the file offset of the frame pointer at the given
+// index of the frame pointer table.
+func pointerOffset(idx int) int64 {
+	return int64(headerSize + framePointerSize*idx)
+}
+
+// fileOrder returns the indices of the frame pointer table, in the order the
+// frame blocks appear in the file.
+func (d *DC6) fileOrder() []int {
+	order := make([]int, len(d.FramePointers))
+	for idx := range order {
+		order[idx] = idx
 	}
 
-	for idx := 0; idx < totalFrames; idx++ {
-		dirIdx := idx / int(framesPerDirection)
-		frameIdx := idx % int(framesPerDirection)
+	sort.SliceStable(order, func(i, j int) bool {
+		return d.FramePointers[order[i]] < d.FramePointers[order[j]]
+	})
+
+	return order
+}
+
+// checkFramePointers cross-checks the frame pointer table against the
+// NextBlock values of the frames. The NextBlock of a frame is the pointer of
+// the block that follows it in the file, which need not be the next frame of
+// the frame pointer table, or the end of its own block for the last block.
+func (d *DC6) checkFramePointers() error {
+	const nextBlockOffset = 24
 
-		if d.Directions[dirIdx] == nil {
-			d.Directions[dirIdx] = &Direction{}
+	// the pointer of the following block of every frame, frames sharing a
+	// frame pointer share a block
+	nextBlocks := make([]int64, len(d.FramePointers))
+	order := d.fileOrder()
+	following := int64(-1)
+
+	for orderIdx := len(order) - 1; orderIdx >= 0; orderIdx-- {
+		idx := order[orderIdx]
+
+		if orderIdx+1 < len(order) && d.FramePointers[order[orderIdx+1]] != d.FramePointers[idx] {
+			following = int64(d.FramePointers[order[orderIdx+1]])
 		}
 
-		if d.Directions[dirIdx].Frames == nil {
-			d.Directions[dirIdx].Frames = make([]*Frame, framesPerDirection)
+		nextBlocks[idx] = following
+	}
+
+	for idx, pointer := range d.FramePointers {
+		frame := d.frameByIndex(idx)
+		if frame.err != nil {
+			continue
 		}
 
-		frame := &Frame{dc6: d}
+		expected := nextBlocks[idx]
+		if expected < 0 {
+			expected = int64(pointer) + frameHeaderSize + int64(frame.Length) + terminatorSize
+		}
 
-		// toss the errors, only check last err
-		frame.Flipped, _ = stream.Next(frameFlippedBytes).Bytes().AsUInt32()
-		frame.Width, _ = stream.Next(frameWidthBytes).Bytes().AsUInt32()
-		frame.Height, _ = stream.Next(frameHeightBytes).Bytes().AsUInt32()
-		frame.OffsetX, _ = stream.Next(frameOffsetXBytes).Bytes().AsInt32()
-		frame.OffsetY, _ = stream.Next(frameOffsetYBytes).Bytes().AsInt32()
-		frame.Unknown, _ = stream.Next(frameUnknownBytes).Bytes().AsUInt32()
-		frame.NextBlock, _ = stream.Next(frameNextBlockBytes).Bytes().AsUInt32()
-		frame.Length, _ = stream.Next(frameLengthBytes).Bytes().AsUInt32()
-		frame.FrameData, _ = stream.Next(int(frame.Length)).Bytes().AsBytes()
-		frame.Terminator, err = stream.Next(terminatorSize).Bytes().AsBytes()
+		if int64(frame.NextBlock) == expected {
+			continue
+		}
 
-		if err != nil {
-			return fmt.Errorf("could not decode body, %w", err)
+		err := fmt.Errorf("%w, next block is %d, expected %d", ErrNextBlockMismatch, frame.NextBlock, expected)
+		if err = frame.anomaly(WarningNextBlockMismatch, int64(pointer)+nextBlockOffset, err); err != nil {
+			return err
 		}
+	}
 
-		d.Directions[dirIdx].Frames[frameIdx] = frame
+	return nil
+}
+
+// isTermination reports whether the given termination bytes are all 0xEE.
+func isTermination(b []byte) bool {
+	for idx := range b {
+		if b[idx] != defaultTermination {
+			return false
+		}
 	}
 
-	for idx := range d.Directions {
-		d.Directions[idx].decodeFrames()
+	return true
+}
+
+// framePosition returns the direction and frame index of the frame at the
+// given index of the frame pointer table.
+func (d *DC6) framePosition(idx int) (dirIdx, frameIdx int) {
+	framesPerDirection := len(d.Directions[0].Frames)
+
+	return idx / framesPerDirection, idx % framesPerDirection
+}
+
+// frameByIndex returns the frame at the given index of the frame pointer table.
+func (d *DC6) frameByIndex(idx int) *Frame {
+	dirIdx, frameIdx := d.framePosition(idx)
+
+	return d.Directions[dirIdx].Frames[frameIdx]
+}
+
+func (d *DC6) setFrame(idx int, frame *Frame) {
+	frame.direction, frame.index = d.framePosition(idx)
+
+	d.Directions[frame.direction].Frames[frame.index] = frame
+}
+
+// decodeFrameHeader reads a frame header from the given reader, yielding a
+// frame without any frame data.
+func (d *DC6) decodeFrameHeader(r io.Reader) (frame *Frame, err error) {
+	const (
+		frameFlippedBytes   = 4
+		frameWidthBytes     = 4
+		frameHeightBytes    = 4
+		frameOffsetXBytes   = 4
+		frameOffsetYBytes   = 4
+		frameUnknownBytes   = 4
+		frameNextBlockBytes = 4
+		frameLengthBytes    = 4
+	)
+
+	data := make([]byte, frameHeaderSize)
+	if _, err = io.ReadFull(r, data); err != nil {
+		return nil, err
 	}
 
-	return nil
+	bitstreamMu.Lock()
+	defer bitstreamMu.Unlock()
+
+	stream := bitstream.NewReader().FromBytes(data...)
+	frame = &Frame{dc6: d}
+
+	// toss the errors, only check last err
+	frame.Flipped, _ = stream.Next(frameFlippedBytes).Bytes().AsUInt32()
+	frame.Width, _ = stream.Next(frameWidthBytes).Bytes().AsUInt32()
+	frame.Height, _ = stream.Next(frameHeightBytes).Bytes().AsUInt32()
+	frame.OffsetX, _ = stream.Next(frameOffsetXBytes).Bytes().AsInt32()
+	frame.OffsetY, _ = stream.Next(frameOffsetYBytes).Bytes().AsInt32()
+	frame.Unknown, _ = stream.Next(frameUnknownBytes).Bytes().AsUInt32()
+	frame.NextBlock, _ = stream.Next(frameNextBlockBytes).Bytes().AsUInt32()
+	frame.Length, err = stream.Next(frameLengthBytes).Bytes().AsUInt32()
+
+	return frame, err
 }
 
-// decodeFrame decodes the given frame to an indexed color texture
-func (d *Direction) decodeFrames() {
+// decodeFrames decodes the frames to indexed color textures
+func (d *Direction) decodeFrames() error {
 	for idx := range d.Frames {
-		d.decodeFrame(idx)
+		// salvaged frames keep their errors, see Frame.Err
+		if err := d.decodeFrame(idx); err != nil && !d.Frames[idx].salvaging() {
+			return err
+		}
 	}
+
+	return nil
 }
 
-func (d *Direction) decodeFrame(frameIndex int) {
-	frame := d.Frames[frameIndex]
+func (d *Direction) decodeFrame(frameIndex int) error {
+	return d.Frames[frameIndex].Decode()
+}
+
+// decodeIndexData decodes the frame's FrameData to an indexed color texture
+// and the mask of its opaque pixels.
+func (f *Frame) decodeIndexData() error {
+	width, height := int(f.Width), int(f.Height)
+
+	// every scanline is terminated by a byte of frame data, frames that are
+	// rejected for having too few bytes are rejected before their pixels
+	// are allocated
+	if height > len(f.FrameData) && !f.salvaging() && f.mode().rejects(WarningUnterminatedScanline) {
+		const fmtErr = "%w, %d bytes of frame data can not terminate %d scanlines"
+		err := fmt.Errorf(fmtErr, ErrBadTerminator, len(f.FrameData), height)
 
-	indexData := make([]byte, frame.Width*frame.Height)
+		return f.anomaly(WarningUnterminatedScanline, f.dataOffset, err)
+	}
+
+	indexData := make([]byte, width*height)
+	mask := make([]bool, width*height)
 	x := 0
-	y := int(frame.Height) - 1
+	y := height - 1
 	offset := 0
 
-loop: // this is a label for the loop, so the switch can break the loop (and not the switch)
-	for {
-		if offset >= len(frame.FrameData) {
-			break
-		}
-
-		b := int(frame.FrameData[offset])
+	for offset < len(f.FrameData) && y >= 0 {
+		b := int(f.FrameData[offset])
 		offset++
 
 		switch scanlineType(b) {
 		case endOfLine:
-			if y == 0 {
-				break loop
-			}
-
 			y--
-
 			x = 0
 		case runOfTransparentPixels:
-			transparentPixels := b & maxRunLength
-			x += transparentPixels
+			x += b & maxRunLength
 		case runOfOpaquePixels:
-			for i := 0; i < b; i++ {
-				index := x + y*int(frame.Width) + i
-				if index < len(indexData) && offset < len(frame.FrameData) {
-					indexData[index] = frame.FrameData[offset]
+			if offset+b > len(f.FrameData) {
+				const fmtErr = "%w, run of %d pixels exceeds frame data"
+				return f.salvageIndexData(indexData, mask, f.decodeError(offset-1, fmt.Errorf(fmtErr, ErrTruncatedFrameData, b)))
+			}
+
+			if x+b > width {
+				const fmtErr = "%w, scanline %d has %d pixels, the frame is %d pixels wide"
+				err := fmt.Errorf(fmtErr, ErrScanlineOverrun, y, x+b, width)
+
+				if err = f.anomaly(WarningScanlineOverrun, f.dataOffset+int64(offset-1), err); err != nil {
+					return f.salvageIndexData(indexData, mask, err)
 				}
-				offset++
 			}
 
+			// pixels beyond the width of the frame are clipped
+			for i := 0; i < b && x+i < width; i++ {
+				indexData[x+y*width+i] = f.FrameData[offset+i]
+				mask[x+y*width+i] = true
+			}
+
+			offset += b
 			x += b
 		}
 	}
 
-	frame.IndexData = indexData
+	if y >= 0 {
+		const fmtErr = "%w, frame data ends before scanline %d is terminated"
+		err := fmt.Errorf(fmtErr, ErrBadTerminator, y)
+
+		if err = f.anomaly(WarningUnterminatedScanline, f.dataOffset+int64(offset), err); err != nil {
+			return f.salvageIndexData(indexData, mask, err)
+		}
+	}
+
+	if offset < len(f.FrameData) {
+		const fmtErr = "%w, %d bytes after the last scanline"
+		err := fmt.Errorf(fmtErr, ErrTrailingData, len(f.FrameData)-offset)
+
+		if err = f.anomaly(WarningTrailingData, f.dataOffset+int64(offset), err); err != nil {
+			return f.salvageIndexData(indexData, mask, err)
+		}
+	}
+
+	if !isTermination(f.Terminator) {
+		const fmtErr = "%w, frame terminator is % x"
+		err := fmt.Errorf(fmtErr, ErrBadTerminator, f.Terminator)
+
+		if err = f.anomaly(WarningBadTermination, f.dataOffset+int64(len(f.FrameData)), err); err != nil {
+			return f.salvageIndexData(indexData, mask, err)
+		}
+	}
+
+	f.IndexData, f.Mask = indexData, mask
+	f.decoded = f.snapshot()
+
+	return nil
+}
+
+// salvageIndexData keeps the partially decoded index data and mask of a frame
+// that could not be decoded, when decoding in salvage mode.
+func (f *Frame) salvageIndexData(indexData []byte, mask []bool, err error) error {
+	if f.salvaging() {
+		f.IndexData, f.Mask = indexData, mask
+	}
+
+	return err
+}
+
+// decodeError creates a DecodeError for the given offset within the frame data.
+func (f *Frame) decodeError(offset int, err error) error {
+	return &DecodeError{Direction: f.direction, Frame: f.index, Offset: f.dataOffset + int64(offset), Err: err}
 }
 
 func scanlineType(b int) scanlineState {
@@ -204,54 +568,141 @@ func scanlineType(b int) scanlineState {
 	return runOfOpaquePixels
 }
 
-// Clone creates a copy of the DC6
+// Clone returns a deep copy of the DC6, the frames of the copy do not share
+// any data with the frames of the DC6.
 func (d *DC6) Clone() *DC6 {
-	clone := *d
+	clone := &DC6{
+		Version:       d.Version,
+		Flags:         d.Flags,
+		Encoding:      d.Encoding,
+		Termination:   cloneBytes(d.Termination),
+		FramePointers: append([]uint32(nil), d.FramePointers...),
+		palette:       append(color.Palette(nil), d.palette...),
+		lut:           d.lut,
+		warnings:      append([]Warning(nil), d.warnings...),
+		mode:          d.mode,
+		salvaging:     d.salvaging,
+		source:        d.source,
+	}
 
-	copy(clone.Termination, d.Termination)
+	for _, g := range d.gaps {
+		clone.addGap(gap{offset: g.offset, length: g.length, data: cloneBytes(g.data)})
+	}
 
-	clone.Directions = make([]*Direction, len(d.Directions))
-	for dirIdx := range d.Directions {
-		clone.Directions[dirIdx].Frames = make([]*Frame, len(d.Directions[dirIdx].Frames))
-		for frameIdx := range d.Directions[dirIdx].Frames {
-			frame := *d.Directions[dirIdx].Frames[frameIdx]
-			clone.Directions[dirIdx].Frames[frameIdx] = &frame
+	if d.Directions != nil {
+		clone.Directions = make([]*Direction, len(d.Directions))
+	}
+
+	for dirIdx, direction := range d.Directions {
+		if direction == nil {
+			continue
+		}
+
+		clone.Directions[dirIdx] = &Direction{dc6: clone}
+
+		if direction.Frames != nil {
+			clone.Directions[dirIdx].Frames = make([]*Frame, len(direction.Frames))
+		}
+
+		for frameIdx, frame := range direction.Frames {
+			if frame != nil {
+				clone.Directions[dirIdx].Frames[frameIdx] = frame.clone(clone)
+			}
 		}
 	}
 
-	return &clone
+	return clone
 }
 
-// Palette returns the current color palette
+// Palette returns the current color palette, or the default palette if no
+// palette was set. The returned palette must not be modified.
 func (d *DC6) Palette() color.Palette {
 	if d.palette == nil {
-		d.SetPalette(nil)
+		return defaultPalette()
 	}
 
 	return d.palette
 }
 
-// SetPalette sets the current color palette
+// SetPalette sets the current color palette, a nil palette selects the
+// default palette. The palette must not be modified after it is set.
 func (d *DC6) SetPalette(p color.Palette) {
 	if p == nil {
-		p = d.getDefaultPalette()
+		d.palette, d.lut = nil, nil
+		return
+	}
+
+	d.palette, d.lut = p, newRGBALUT(p)
+}
+
+// rgbaLUT returns the lookup table of the current color palette.
+func (d *DC6) rgbaLUT() *rgbaLUT {
+	if d.lut == nil {
+		return defaultRGBALUT()
+	}
+
+	return d.lut
+}
+
+// Palettes is the registry of named palettes used by SetPaletteByName. Its
+// default palette, see palette.Registry.SetDefault, is the palette of DC6s
+// without a palette. If it has no default palette, the debug palette (see
+// palette.Debug) is used instead.
+var Palettes = palette.NewRegistry()
+
+// SetPaletteByName sets the palette registered in Palettes under the given
+// name as the current color palette.
+func (d *DC6) SetPaletteByName(name string) error {
+	p, err := Palettes.Lookup(name)
+	if err != nil {
+		return fmt.Errorf("could not set palette, %w", err)
 	}
 
-	d.palette = p
+	d.SetPalette(p)
+
+	return nil
+}
+
+var (
+	debugPalette = palette.Debug()
+	debugRGBALUT = newRGBALUT(debugPalette)
+)
+
+// defaultLUT caches the lookup table of the default palette of Palettes.
+var defaultLUT struct {
+	mu      sync.Mutex
+	palette color.Palette
+	lut     *rgbaLUT
 }
 
-func (d *DC6) getDefaultPalette() color.Palette {
-	const numColors = 256
+// defaultPalette returns the palette of DC6s without a palette, and of frames
+// that do not belong to a DC6.
+func defaultPalette() color.Palette {
+	if p := Palettes.Default(); p != nil {
+		return p
+	}
 
-	palette := make(color.Palette, numColors)
+	return debugPalette
+}
 
-	for idx := range palette {
-		rgb := uint8(idx)
-		c := color.RGBA{}
-		c.R, c.G, c.B, c.A = rgb, rgb, rgb, math.MaxUint8
+// defaultRGBALUT returns the lookup table of defaultPalette.
+func defaultRGBALUT() *rgbaLUT {
+	p := Palettes.Default()
+	if p == nil {
+		return debugRGBALUT
+	}
 
-		palette[idx] = c
+	defaultLUT.mu.Lock()
+	defer defaultLUT.mu.Unlock()
+
+	if !samePalette(p, defaultLUT.palette) {
+		defaultLUT.palette, defaultLUT.lut = p, newRGBALUT(p)
 	}
 
-	return palette
+	return defaultLUT.lut
+}
+
+// samePalette reports whether both palettes are the same slice.
+func samePalette(a, b color.Palette) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
 }
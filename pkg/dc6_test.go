@@ -0,0 +1,28 @@
+package pkg
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/gravestench/dc6/pkg/palette"
+)
+
+func TestSetPaletteByName(t *testing.T) {
+	p := color.Palette{color.RGBA{}, red}
+	Palettes.Register("Test-Palette", p)
+
+	d := New(1, 1)
+
+	if err := d.SetPaletteByName("test-palette"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(d.palette) != len(p) || d.palette[1] != red {
+		t.Errorf("expected the registered palette, got %v", d.palette)
+	}
+
+	if err := d.SetPaletteByName("missing"); !errors.Is(err, palette.ErrUnknownPalette) {
+		t.Errorf("expected ErrUnknownPalette, got %v", err)
+	}
+}
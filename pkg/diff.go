@@ -0,0 +1,181 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+)
+
+// Difference describes a difference between two DC6s, see DC6.Diff.
+type Difference struct {
+	Direction int    // -1 if the difference is not about a specific direction
+	Frame     int    // -1 if the difference is not about a specific frame
+	Field     string // the name of the field that differs, or "Pixels"
+	Message   string
+
+	// Pixels holds the locations of the pixels that differ, in the coordinate
+	// space of the Bounds of the frame of the first DC6. It is only set when
+	// the Field is "Pixels".
+	Pixels []image.Point
+}
+
+func (d Difference) String() string {
+	switch {
+	case d.Direction < 0:
+		return fmt.Sprintf("%s: %s", d.Field, d.Message)
+	case d.Frame < 0:
+		return fmt.Sprintf("direction %d, %s: %s", d.Direction, d.Field, d.Message)
+	default:
+		return fmt.Sprintf("direction %d frame %d, %s: %s", d.Direction, d.Frame, d.Field, d.Message)
+	}
+}
+
+// Equal reports whether the DC6 has the same header fields, directions,
+// frame header fields and pixels as the other DC6, see Diff.
+func (d *DC6) Equal(other *DC6) bool {
+	return len(d.Diff(other)) == 0
+}
+
+// Diff returns the differences between the DC6 and the other DC6. The header
+// fields of the DC6 and its frames are compared, as well as the pixels of the
+// frames. Two pixels differ if one of them is transparent and the other is
+// not, or if both are opaque and have a different palette index.
+//
+// Values that follow from the encoded frame data (the frame pointers, and the
+// NextBlock and Length of the frames) and the palette are not compared. Frames
+// are decoded as needed.
+func (d *DC6) Diff(other *DC6) []Difference {
+	var diffs []Difference
+
+	field := func(dir, frame int, name string, a, b interface{}) {
+		diffs = append(diffs, Difference{
+			Direction: dir,
+			Frame:     frame,
+			Field:     name,
+			Message:   fmt.Sprintf("%v != %v", a, b),
+		})
+	}
+
+	if d.Version != other.Version {
+		field(-1, -1, "Version", d.Version, other.Version)
+	}
+
+	if d.Flags != other.Flags {
+		field(-1, -1, "Flags", d.Flags, other.Flags)
+	}
+
+	if d.Encoding != other.Encoding {
+		field(-1, -1, "Encoding", d.Encoding, other.Encoding)
+	}
+
+	if !bytes.Equal(d.Termination, other.Termination) {
+		field(-1, -1, "Termination", fmt.Sprintf("% x", d.Termination), fmt.Sprintf("% x", other.Termination))
+	}
+
+	if len(d.Directions) != len(other.Directions) {
+		field(-1, -1, "Directions", len(d.Directions), len(other.Directions))
+	}
+
+	for dirIdx := 0; dirIdx < len(d.Directions) && dirIdx < len(other.Directions); dirIdx++ {
+		a, b := d.Directions[dirIdx], other.Directions[dirIdx]
+
+		if a == nil || b == nil {
+			if a != b {
+				field(dirIdx, -1, "Direction", nilness(a == nil), nilness(b == nil))
+			}
+
+			continue
+		}
+
+		if len(a.Frames) != len(b.Frames) {
+			field(dirIdx, -1, "Frames", len(a.Frames), len(b.Frames))
+		}
+
+		for frameIdx := 0; frameIdx < len(a.Frames) && frameIdx < len(b.Frames); frameIdx++ {
+			diffs = append(diffs, diffFrames(dirIdx, frameIdx, a.Frames[frameIdx], b.Frames[frameIdx])...)
+		}
+	}
+
+	return diffs
+}
+
+// diffFrames returns the differences between two frames at the same position.
+func diffFrames(dirIdx, frameIdx int, a, b *Frame) []Difference {
+	var diffs []Difference
+
+	field := func(name string, va, vb interface{}) {
+		diffs = append(diffs, Difference{
+			Direction: dirIdx,
+			Frame:     frameIdx,
+			Field:     name,
+			Message:   fmt.Sprintf("%v != %v", va, vb),
+		})
+	}
+
+	if a == nil || b == nil {
+		if a != b {
+			field("Frame", nilness(a == nil), nilness(b == nil))
+		}
+
+		return diffs
+	}
+
+	fields := []struct {
+		name   string
+		va, vb interface{}
+	}{
+		{"Flipped", a.Flipped, b.Flipped},
+		{"Width", a.Width, b.Width},
+		{"Height", a.Height, b.Height},
+		{"OffsetX", a.OffsetX, b.OffsetX},
+		{"OffsetY", a.OffsetY, b.OffsetY},
+		{"Unknown", a.Unknown, b.Unknown},
+	}
+
+	for _, f := range fields {
+		if f.va != f.vb {
+			field(f.name, f.va, f.vb)
+		}
+	}
+
+	// pixels can only be compared between frames of the same size
+	if a.Width != b.Width || a.Height != b.Height {
+		return diffs
+	}
+
+	indexA, indexB := a.indexData(), b.indexData()
+	width := int(a.Width)
+	origin := a.Bounds().Min
+
+	var pixels []image.Point
+
+	for idx := 0; idx < width*int(a.Height); idx++ {
+		opaqueA, opaqueB := a.opaque(idx, indexA), b.opaque(idx, indexB)
+
+		if opaqueA == opaqueB && (!opaqueA || indexA[idx] == indexB[idx]) {
+			continue
+		}
+
+		pixels = append(pixels, origin.Add(image.Point{X: idx % width, Y: idx / width}))
+	}
+
+	if len(pixels) > 0 {
+		diffs = append(diffs, Difference{
+			Direction: dirIdx,
+			Frame:     frameIdx,
+			Field:     "Pixels",
+			Message:   fmt.Sprintf("%d pixels differ", len(pixels)),
+			Pixels:    pixels,
+		})
+	}
+
+	return diffs
+}
+
+func nilness(isNil bool) string {
+	if isNil {
+		return "nil"
+	}
+
+	return "not nil"
+}
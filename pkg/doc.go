@@ -1,2 +1,20 @@
 // Package d2dc6 contains the logic for loading and processing DC6 files.
+//
+// # Concurrency
+//
+// A decoded DC6 can be read by multiple goroutines at the same time. This
+// covers the methods that do not modify the DC6 or its frames: Palette,
+// Header, FrameHeaders, Warnings, FrameErrors, ToPaletted, Encode, ToBytes
+// and Clone of a DC6, and Decode, Err, Warnings, IsDirty, the
+// image.PalettedImage methods, IsTransparent, ToImageRGBA, ToPaletted and
+// DrawInto of a frame.
+// Frames that are decoded on first use (see DecodeOptions.Lazy and Open) are
+// decoded once, no matter how many goroutines use them. The functions that
+// decode DC6 files can be called from multiple goroutines at the same time.
+//
+// The Palettes registry can be used by multiple goroutines at the same time.
+//
+// Modifying a DC6, for example with SetPalette, MarkDirty or by assigning
+// to its fields or the fields of its frames, is not safe while other
+// goroutines use the DC6.
 package pkg
@@ -0,0 +1,223 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	headerSize       = 24 // version, flags, encoding, termination, directions, frames per direction
+	framePointerSize = 4
+	frameHeaderSize  = 32 // flipped, width, height, offset x/y, unknown, next block, length
+	terminatorSize   = 3
+)
+
+const defaultTermination = 0xee
+
+// ToBytes encodes the DC6 and returns the resulting file data.
+func (d *DC6) ToBytes() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	if err := d.Encode(buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Encode writes the DC6 file data to the given writer. Frames that are not
+// dirty (see Frame.IsDirty) are written using their original FrameData.
+//
+// If no frame is dirty, and the frames are where they were decoded from, the
+// DC6 is written with the layout of the file it was decoded from: the same
+// frame pointers, NextBlock values, order of the frame blocks, padding between
+// them and data after the last of them. So a DC6 that is decoded and encoded
+// without any edits yields the same bytes it was decoded from, except for the
+// data after the last frame block, which DecodeOptions.Decode does not read.
+//
+// Otherwise the frame blocks are written in the order of the frame pointer
+// table without any padding, and the frame pointers, NextBlock and Length
+// values are computed from the encoded frame data.
+func (d *DC6) Encode(w io.Writer) error {
+	if err := d.Validate(); err != nil {
+		return fmt.Errorf("could not encode dc6, %w", err)
+	}
+
+	numDirections := len(d.Directions)
+	framesPerDirection := len(d.Directions[0].Frames)
+	totalFrames := numDirections * framesPerDirection
+	encoded := make([][]byte, 0, totalFrames)
+	anyDirty := false
+
+	for dirIdx := range d.Directions {
+		for frameIdx, frame := range d.Directions[dirIdx].Frames {
+			if !frame.IsDirty() {
+				data, err := frame.frameData()
+				if err != nil {
+					return fmt.Errorf("could not encode direction %d frame %d, %w", dirIdx, frameIdx, err)
+				}
+
+				encoded = append(encoded, data)
+
+				continue
+			}
+
+			anyDirty = true
+
+			data, err := frame.encode()
+			if err != nil {
+				return fmt.Errorf("could not encode direction %d frame %d, %w", dirIdx, frameIdx, err)
+			}
+
+			encoded = append(encoded, data)
+		}
+	}
+
+	if !anyDirty && d.keepsLayout(encoded) {
+		return d.encodeLayout(w, encoded)
+	}
+
+	pointers := make([]uint32, totalFrames)
+	pointer := headerSize + framePointerSize*totalFrames
+
+	for idx := range encoded {
+		pointers[idx] = uint32(pointer)
+		pointer += frameHeaderSize + len(encoded[idx]) + terminatorSize
+	}
+
+	buf := &bytes.Buffer{}
+
+	d.encodeHeader(buf, uint32(numDirections), uint32(framesPerDirection))
+
+	for idx := range pointers {
+		writeUint32(buf, pointers[idx])
+	}
+
+	for idx := range encoded {
+		frame := d.Directions[idx/framesPerDirection].Frames[idx%framesPerDirection]
+
+		nextBlock := uint32(pointer)
+		if idx+1 < len(pointers) {
+			nextBlock = pointers[idx+1]
+		}
+
+		frame.encodeHeader(buf, nextBlock, uint32(len(encoded[idx])))
+		buf.Write(encoded[idx])
+		buf.Write(terminationBytes(frame.Terminator, terminatorSize))
+	}
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+func (d *DC6) encodeHeader(buf *bytes.Buffer, numDirections, framesPerDirection uint32) {
+	writeUint32(buf, uint32(d.Version))
+	writeUint32(buf, d.Flags)
+	writeUint32(buf, d.Encoding)
+	buf.Write(terminationBytes(d.Termination, 4))
+	writeUint32(buf, numDirections)
+	writeUint32(buf, framesPerDirection)
+}
+
+func (f *Frame) encodeHeader(buf *bytes.Buffer, nextBlock, length uint32) {
+	writeUint32(buf, f.Flipped)
+	writeUint32(buf, f.Width)
+	writeUint32(buf, f.Height)
+	writeUint32(buf, uint32(f.OffsetX))
+	writeUint32(buf, uint32(f.OffsetY))
+	writeUint32(buf, f.Unknown)
+	writeUint32(buf, nextBlock)
+	writeUint32(buf, length)
+}
+
+// encode run-length encodes the frame's IndexData, this is the inverse of
+// Frame.decodeIndexData. Scanlines are written from the bottom of the frame
+// to the top, pixels are transparent according to the Mask, or have palette
+// index 0 if the frame has no Mask. Frame data that was not decoded yet, like
+// that of a frame marked dirty before its first use, is decoded first.
+func (f *Frame) encode() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.IndexData == nil && (f.FrameData != nil || f.source != nil) {
+		if err := f.decode(); err != nil {
+			return nil, err
+		}
+	}
+
+	width, height := int(f.Width), int(f.Height)
+
+	if len(f.IndexData) < width*height {
+		const fmtErr = "index data has %d pixels, expected %d"
+		return nil, fmt.Errorf(fmtErr, len(f.IndexData), width*height)
+	}
+
+	if f.Mask != nil && len(f.Mask) < width*height {
+		const fmtErr = "mask has %d pixels, expected %d"
+		return nil, fmt.Errorf(fmtErr, len(f.Mask), width*height)
+	}
+
+	buf := &bytes.Buffer{}
+
+	for y := height - 1; y >= 0; y-- {
+		row := f.IndexData[y*width : (y+1)*width]
+		opaque := func(x int) bool { return f.opaque(y*width+x, f.IndexData) }
+		x := 0
+
+		for x < len(row) {
+			start := x
+
+			if !opaque(x) {
+				for x < len(row) && !opaque(x) {
+					x++
+				}
+
+				if x == len(row) {
+					break // trailing transparent pixels are implied by the end of the scanline
+				}
+
+				for run := x - start; run > 0; run -= maxRunLength {
+					n := run
+					if n > maxRunLength {
+						n = maxRunLength
+					}
+
+					buf.WriteByte(endOfScanLine | byte(n))
+				}
+
+				continue
+			}
+
+			for x < len(row) && opaque(x) && x-start < maxRunLength {
+				x++
+			}
+
+			buf.WriteByte(byte(x - start))
+			buf.Write(row[start:x])
+		}
+
+		buf.WriteByte(endOfScanLine)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// terminationBytes returns the given termination bytes, or the default
+// termination bytes if the given slice is not of the expected size.
+func terminationBytes(b []byte, size int) []byte {
+	if len(b) == size {
+		return b
+	}
+
+	return bytes.Repeat([]byte{defaultTermination}, size)
+}
+
+func writeUint32(buf *bytes.Buffer, v uint32) {
+	var b [4]byte
+
+	binary.LittleEndian.PutUint32(b[:], v)
+	buf.Write(b[:])
+}
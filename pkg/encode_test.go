@@ -0,0 +1,214 @@
+package pkg
+
+import (
+	"bytes"
+	"testing"
+)
+
+// roundTrip encodes and decodes the given DC6.
+func roundTrip(t *testing.T, d *DC6) *DC6 {
+	t.Helper()
+
+	data, err := d.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := FromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return decoded
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, size := range [][2]int{{1, 1}, {1, 3}, {4, 2}, {8, 4}} {
+		d := newTestDC6(size[0], size[1])
+		d.Flags, d.Encoding = 3, 1
+
+		if got := roundTrip(t, d); !got.Equal(d) {
+			t.Errorf("%dx%d frames: %v", size[0], size[1], got.Diff(d))
+		}
+	}
+}
+
+func TestRoundTripLongRuns(t *testing.T) {
+	const width = 2*maxRunLength + 50
+
+	frame := &Frame{
+		Width:     width,
+		Height:    3,
+		IndexData: make([]byte, width*3),
+		Mask:      make([]bool, width*3),
+	}
+
+	// an opaque row, a transparent row and a row with opaque pixels at its ends
+	for x := 0; x < width; x++ {
+		frame.IndexData[x], frame.Mask[x] = byte(x), true
+	}
+
+	frame.IndexData[2*width], frame.Mask[2*width] = 7, true
+	frame.IndexData[3*width-1], frame.Mask[3*width-1] = 9, true
+
+	d := New(1, 2)
+	d.Directions[0].AddFrame(frame)
+	d.Directions[0].AddFrame(&Frame{})
+
+	if got := roundTrip(t, d); !got.Equal(d) {
+		t.Error(got.Diff(d))
+	}
+}
+
+func TestReencodeUnchanged(t *testing.T) {
+	data, err := newTestDC6(2, 3).ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	padded := relayout(data, 5)
+	trailing := append(relayout(data, 2), "trailing data"...)
+
+	files := map[string][]byte{"contiguous": data, "padded": padded, "trailing": trailing}
+
+	for decoderName, decode := range decoders(DecodeOptions{}) {
+		for fileName, file := range files {
+			d, err := decode(file)
+			if err != nil {
+				t.Fatalf("%s %s: %v", decoderName, fileName, err)
+			}
+
+			got, err := d.ToBytes()
+			if err != nil {
+				t.Fatalf("%s %s: %v", decoderName, fileName, err)
+			}
+
+			want := file
+			if decoderName == "Decode" && fileName == "trailing" {
+				// Decode does not read the data after the last frame
+				want = trailing[:len(trailing)-len("trailing data")]
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s %s: re-encoded file differs", decoderName, fileName)
+			}
+
+			if got, err := d.Clone().ToBytes(); err != nil || !bytes.Equal(got, want) {
+				t.Errorf("%s %s: re-encoded clone differs, %v", decoderName, fileName, err)
+			}
+		}
+	}
+}
+
+func TestReencodeEdited(t *testing.T) {
+	data, err := newTestDC6(2, 3).ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := DecodeOptions{Lazy: true}.FromBytes(relayout(data, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edited := d.Directions[1].Frames[2]
+	if err := edited.Decode(); err != nil {
+		t.Fatal(err)
+	}
+
+	edited.IndexData[0], edited.Mask[0] = 42, true
+
+	if !edited.IsDirty() || d.Directions[0].Frames[0].IsDirty() {
+		t.Fatal("only the edited frame should be dirty")
+	}
+
+	got, err := d.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the frames are written without padding
+	if len(got) >= len(relayout(data, 5)) {
+		t.Errorf("expected the padding to be dropped, got %d bytes", len(got))
+	}
+
+	decoded, err := DecodeOptions{Mode: ModeStrict}.FromBytes(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.Equal(d) {
+		t.Error(decoded.Diff(d))
+	}
+
+	// untouched frames keep their original frame data
+	for dirIdx, direction := range d.Directions {
+		for frameIdx, frame := range direction.Frames {
+			if frame != edited && !bytes.Equal(frame.FrameData, decoded.Directions[dirIdx].Frames[frameIdx].FrameData) {
+				t.Errorf("direction %d frame %d was re-encoded", dirIdx, frameIdx)
+			}
+		}
+	}
+}
+
+func TestReencodeReusedBuffer(t *testing.T) {
+	data, err := newTestDC6(2, 3).ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := append(relayout(data, 4), "trailing data"...)
+
+	for name, o := range map[string]DecodeOptions{"FromBytes": {}, "Lazy": {Lazy: true}} {
+		buf := append([]byte(nil), file...)
+
+		d, err := o.FromBytes(buf)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		// the buffer is reused for another file
+		for idx := range buf {
+			buf[idx] = 0xab
+		}
+
+		for _, direction := range d.Directions {
+			for _, frame := range direction.Frames {
+				if err := frame.Decode(); err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+			}
+		}
+
+		if got, err := d.ToBytes(); err != nil || !bytes.Equal(got, file) {
+			t.Errorf("%s: re-encoded file differs, %v", name, err)
+		}
+	}
+}
+
+func TestReencodeMarkedDirty(t *testing.T) {
+	want := newTestDC6(2, 3)
+
+	data, err := want.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, decode := range decoders(DecodeOptions{}) {
+		d, err := decode(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		// frames are marked dirty before they were decoded
+		for _, direction := range d.Directions {
+			for _, frame := range direction.Frames {
+				frame.MarkDirty()
+			}
+		}
+
+		if got := roundTrip(t, d); !got.Equal(want) {
+			t.Errorf("%s: %v", name, got.Diff(want))
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors that a DecodeError may wrap, use errors.Is to test for them.
+var (
+	ErrTruncatedHeader    = errors.New("truncated header")
+	ErrBadVersion         = errors.New("bad version")
+	ErrTruncatedFrameData = errors.New("truncated frame data")
+	ErrPointerOutOfRange  = errors.New("frame pointer out of range")
+	ErrOversizedFrame     = errors.New("oversized frame")
+	ErrBadTerminator      = errors.New("bad terminator")
+	ErrLimitExceeded      = errors.New("resource limit exceeded")
+	ErrNextBlockMismatch  = errors.New("next block mismatch")
+	ErrTrailingData       = errors.New("trailing data")
+	ErrScanlineOverrun    = errors.New("scanline overrun")
+)
+
+// DecodeError is the error returned when a DC6, or one of its frames, can
+// not be decoded. It records where in the file decoding failed.
+type DecodeError struct {
+	Direction int   // -1 if the error is not about a specific frame
+	Frame     int   // -1 if the error is not about a specific frame
+	Offset    int64 // the file offset at which decoding failed
+	Err       error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Direction < 0 {
+		return fmt.Sprintf("could not decode dc6 at offset %d, %v", e.Offset, e.Err)
+	}
+
+	const fmtErr = "could not decode direction %d frame %d at offset %d, %v"
+
+	return fmt.Sprintf(fmtErr, e.Direction, e.Frame, e.Offset, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// headerError creates a DecodeError that is not about a specific frame.
+func headerError(offset int64, err error) error {
+	return &DecodeError{Direction: -1, Frame: -1, Offset: offset, Err: err}
+}
+
+// frameError creates a DecodeError for the frame at the given index of the
+// frame pointer table.
+func (d *DC6) frameError(idx int, offset int64, err error) error {
+	dirIdx, frameIdx := d.framePosition(idx)
+
+	return &DecodeError{Direction: dirIdx, Frame: frameIdx, Offset: offset, Err: err}
+}
+
+// wrapErr annotates a sentinel error with the error that caused it, keeping
+// the sentinel available to errors.Is.
+func wrapErr(sentinel, cause error) error {
+	return fmt.Errorf("%w (%v)", sentinel, cause)
+}
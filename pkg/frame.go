@@ -1,8 +1,13 @@
 package pkg
 
 import (
+	"bytes"
+	"hash/crc32"
 	"image"
 	"image/color"
+	"io"
+	"sync"
+	"sync/atomic"
 )
 
 var _ image.PalettedImage = &Frame{}
@@ -21,18 +26,304 @@ type Frame struct {
 	FrameData  []byte // size is the value of Length
 	Terminator []byte // 3 bytes
 	IndexData  []byte
+	Mask       []bool // true for opaque pixels, nil means palette index 0 is transparent
+	decoded    frameSnapshot
+	dirty      bool
+	source     io.ReaderAt // set when the DC6 was opened with Open
+	dataOffset int64       // file offset of the frame data
+	direction  int         // direction index, as decoded from the file
+	index      int         // frame index within the direction, as decoded from the file
+	warnings   []Warning
+	err        error      // set when the frame could not be salvaged completely
+	mu         sync.Mutex // guards decoding the frame on first use
+	ready      atomic.Bool
 }
 
+// frameSnapshot records the state of a frame right after its FrameData was
+// decoded, so that edits to the IndexData or Mask can be detected when encoding.
+type frameSnapshot struct {
+	valid         bool
+	width, height uint32
+	checksum      uint32
+	mask          uint32
+}
+
+func (f *Frame) snapshot() frameSnapshot {
+	return frameSnapshot{
+		valid:    true,
+		width:    f.Width,
+		height:   f.Height,
+		checksum: crc32.ChecksumIEEE(f.IndexData),
+		mask:     maskChecksum(f.Mask),
+	}
+}
+
+// maskChecksum returns the checksum of a mask, a nil mask is distinguished
+// from a mask without opaque pixels.
+func maskChecksum(mask []bool) uint32 {
+	if mask == nil {
+		return 0
+	}
+
+	// the mask is hashed in chunks, so that no copy of it is allocated
+	var chunk [4096]byte
+
+	hash := crc32.NewIEEE()
+
+	for len(mask) > 0 {
+		n := len(chunk)
+		if len(mask) < n {
+			n = len(mask)
+		}
+
+		for idx, opaque := range mask[:n] {
+			chunk[idx] = 0
+			if opaque {
+				chunk[idx] = 1
+			}
+		}
+
+		_, _ = hash.Write(chunk[:n])
+		mask = mask[n:]
+	}
+
+	_, _ = hash.Write([]byte{1})
+
+	return hash.Sum32()
+}
+
+// clone returns a deep copy of the frame that belongs to the given DC6. It is
+// also used for frames that share a frame block with another frame.
+func (f *Frame) clone(dc6 *DC6) *Frame {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return &Frame{
+		dc6:        dc6,
+		Flipped:    f.Flipped,
+		Width:      f.Width,
+		Height:     f.Height,
+		OffsetX:    f.OffsetX,
+		OffsetY:    f.OffsetY,
+		Unknown:    f.Unknown,
+		NextBlock:  f.NextBlock,
+		Length:     f.Length,
+		FrameData:  cloneBytes(f.FrameData),
+		Terminator: cloneBytes(f.Terminator),
+		IndexData:  cloneBytes(f.IndexData),
+		Mask:       cloneMask(f.Mask),
+		decoded:    f.decoded,
+		dirty:      f.dirty,
+		source:     f.source,
+		dataOffset: f.dataOffset,
+		direction:  f.direction,
+		index:      f.index,
+		warnings:   append([]Warning(nil), f.warnings...),
+		err:        f.err,
+	}
+}
+
+// cloneBytes copies a byte slice, keeping nil slices nil.
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	return append([]byte{}, b...)
+}
+
+// cloneMask copies a mask, keeping nil masks nil.
+func cloneMask(mask []bool) []bool {
+	if mask == nil {
+		return nil
+	}
+
+	return append([]bool{}, mask...)
+}
+
+// IsDirty reports whether the frame's IndexData, Mask (or dimensions) no longer
+// match the FrameData it was decoded from. Dirty frames are re-encoded by
+// DC6.Encode, clean frames have their original FrameData written as-is. Edits
+// are detected by checksums, a frame whose checksums are unchanged is decoded
+// again to make sure it was not edited.
+func (f *Frame) IsDirty() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.dirty || (f.FrameData == nil && f.source == nil) {
+		return true
+	}
+
+	if !f.decoded.valid {
+		// frame data that was never decoded can not have been edited
+		return f.IndexData != nil || f.Mask != nil
+	}
+
+	if f.decoded != f.snapshot() {
+		return true
+	}
+
+	// the checksums match, which does not rule out an edit
+	return !f.matchesFrameData()
+}
+
+// matchesFrameData reports whether the IndexData and Mask are exactly those
+// the FrameData decodes to. The caller must hold f.mu.
+func (f *Frame) matchesFrameData() bool {
+	original := &Frame{
+		dc6:        f.dc6,
+		Width:      f.Width,
+		Height:     f.Height,
+		FrameData:  f.FrameData,
+		Terminator: f.Terminator,
+		dataOffset: f.dataOffset,
+		direction:  f.direction,
+		index:      f.index,
+	}
+
+	if original.decodeIndexData() != nil || !bytes.Equal(original.IndexData, f.IndexData) {
+		return false
+	}
+
+	if len(original.Mask) != len(f.Mask) {
+		return false
+	}
+
+	for idx := range f.Mask {
+		if original.Mask[idx] != f.Mask[idx] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MarkDirty forces the frame to be re-encoded from its IndexData.
+func (f *Frame) MarkDirty() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.dirty = true
+}
+
+// Decode decodes the frame's FrameData into IndexData and Mask, if this has not
+// happened yet. If the DC6 was opened with Open, the frame data is read from
+// the underlying reader first.
+//
+// Frames of a DC6 decoded lazily (see DecodeOptions.Lazy) or opened with Open
+// are decoded on first use by the image.PalettedImage methods and ToImageRGBA,
+// calling Decode up front is only needed to check for errors.
+//
+// When decoding in salvage mode, the IndexData of a frame that can not be
+// decoded completely holds whatever pixels could be recovered, the error is
+// returned and available from Frame.Err.
+func (f *Frame) Decode() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.decode()
+}
+
+// decode decodes the frame, the caller must hold f.mu.
+func (f *Frame) decode() error {
+	if f.IndexData != nil {
+		return f.err
+	}
+
+	err := f.load()
+	if err != nil && !f.salvaging() {
+		return err
+	}
+
+	if decodeErr := f.decodeIndexData(); err == nil {
+		err = decodeErr
+	}
+
+	if err != nil && f.salvaging() {
+		f.fail(err)
+	}
+
+	if f.err != nil {
+		return f.err
+	}
+
+	return err
+}
+
+// indexData returns the IndexData, decoding the frame on first use. Frames
+// that can not be decoded are not decoded again on later uses.
+func (f *Frame) indexData() []byte {
+	if !f.ready.Load() {
+		f.mu.Lock()
+
+		if f.IndexData == nil {
+			_ = f.decode()
+		}
+
+		f.ready.Store(true)
+		f.mu.Unlock()
+	}
+
+	return f.IndexData
+}
+
+// ColorIndexAt returns the palette index of the pixel at the given location,
+// in the coordinate space of Bounds. Transparent pixels, and locations outside
+// of the frame, yield 0.
 func (f *Frame) ColorIndexAt(x, y int) uint8 {
-	idx := (y * int(f.Width)) + x
+	if !(image.Point{X: x, Y: y}.In(f.Bounds())) {
+		return 0
+	}
+
+	indexData := f.indexData()
+
+	idx := (y-int(f.OffsetY))*int(f.Width) + (x - int(f.OffsetX))
+	if !f.opaque(idx, indexData) {
+		return 0
+	}
 
-	return f.IndexData[idx]
+	return indexData[idx]
 }
 
+// IsTransparent reports whether the pixel at the given location, in the
+// coordinate space of Bounds, is transparent. Locations outside of the frame
+// are transparent. Without a Mask, pixels with palette index 0 are transparent.
+func (f *Frame) IsTransparent(x, y int) bool {
+	if !(image.Point{X: x, Y: y}.In(f.Bounds())) {
+		return true
+	}
+
+	indexData := f.indexData()
+
+	idx := (y-int(f.OffsetY))*int(f.Width) + (x - int(f.OffsetX))
+
+	return !f.opaque(idx, indexData)
+}
+
+// opaque reports whether the pixel at the given index of the index data is
+// opaque, according to the Mask if there is one. Pixels missing from the index
+// data are transparent.
+func (f *Frame) opaque(idx int, indexData []byte) bool {
+	if idx >= len(indexData) {
+		return false
+	}
+
+	if f.Mask != nil {
+		return idx < len(f.Mask) && f.Mask[idx]
+	}
+
+	return indexData[idx] != 0
+}
+
+// ColorModel returns the palette of the DC6 the frame belongs to, as used by
+// ToPaletted: it has 256 entries, entry 0 and entries missing from the palette
+// are transparent. So the colors of ColorIndexAt agree with At, except for
+// opaque pixels with palette index 0 (see Mask).
 func (f *Frame) ColorModel() color.Model {
-	return color.RGBAModel
+	return transparentPalette(f.palette())
 }
 
+// Bounds returns the frame's rectangle, which is offset by OffsetX and OffsetY.
 func (f *Frame) Bounds() image.Rectangle {
 	origin := image.Point{X: int(f.OffsetX), Y: int(f.OffsetY)}
 	delta := image.Point{X: int(f.Width), Y: int(f.Height)}
@@ -43,26 +334,29 @@ func (f *Frame) Bounds() image.Rectangle {
 	}
 }
 
+// At returns the color of the pixel at the given location, in the coordinate
+// space of Bounds. Transparent pixels, and locations outside of the frame,
+// yield color.Transparent.
 func (f *Frame) At(x, y int) color.Color {
-	if f.dc6.palette == nil {
-		f.dc6.SetPalette(nil)
+	if f.IsTransparent(x, y) {
+		return color.Transparent
 	}
 
-	cidx := f.ColorIndexAt(x, y)
+	palette := f.palette()
 
-	return f.dc6.palette[cidx]
-}
+	cidx := int(f.ColorIndexAt(x, y))
+	if cidx >= len(palette) {
+		return color.Transparent
+	}
 
-func (f *Frame) ToImageRGBA() *image.RGBA {
-	img := image.NewRGBA(image.Rectangle{
-		Max: f.Bounds().Size(),
-	})
+	return palette[cidx]
+}
 
-	for py := 0; py < int(f.Height); py++ {
-		for px := 0; px < int(f.Width); px++ {
-			img.Set(px, py, f.At(px, py))
-		}
+// palette returns the palette of the DC6 the frame belongs to.
+func (f *Frame) palette() color.Palette {
+	if f.dc6 == nil {
+		return defaultPalette()
 	}
 
-	return img
+	return f.dc6.Palette()
 }
@@ -1,5 +1,7 @@
 package pkg
 
+import "io"
+
 // FrameHeader represents the header of a frame in a DC6.
 type FrameHeader struct {
 	Flipped   int32  `struct:"int32"`
@@ -11,3 +13,47 @@ type FrameHeader struct {
 	NextBlock uint32 `struct:"uint32"`
 	Length    uint32 `struct:"uint32"`
 }
+
+// ReadFrameHeaders reads the frame headers of every frame of the DC6 file read
+// from r, ordered by direction and then frame. The frame data is not read.
+func ReadFrameHeaders(r io.ReaderAt, size int64) ([]FrameHeader, error) {
+	d, err := Open(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.FrameHeaders(), nil
+}
+
+// Header returns the frame header of the frame.
+func (f *Frame) Header() FrameHeader {
+	return FrameHeader{
+		Flipped:   int32(f.Flipped),
+		Width:     int32(f.Width),
+		Height:    int32(f.Height),
+		OffsetX:   f.OffsetX,
+		OffsetY:   f.OffsetY,
+		Unknown:   f.Unknown,
+		NextBlock: f.NextBlock,
+		Length:    f.Length,
+	}
+}
+
+// FrameHeaders returns the frame headers of every frame of the DC6, ordered
+// by direction and then frame.
+func (d *DC6) FrameHeaders() []FrameHeader {
+	headers := make([]FrameHeader, 0)
+
+	for dirIdx := range d.Directions {
+		for _, frame := range d.Directions[dirIdx].Frames {
+			if frame == nil {
+				headers = append(headers, FrameHeader{})
+				continue
+			}
+
+			headers = append(headers, frame.Header())
+		}
+	}
+
+	return headers
+}
@@ -0,0 +1,57 @@
+package pkg
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestFramePalettedImage(t *testing.T) {
+	d := newTestDC6(1, 2)
+
+	// palette index 0 is opaque in the debug palette
+	if _, _, _, a := debugPalette[0].RGBA(); a == 0 {
+		t.Fatal("expected an opaque color at index 0 of the debug palette")
+	}
+
+	for _, frame := range d.Directions[0].Frames {
+		model, ok := frame.ColorModel().(color.Palette)
+		if !ok || len(model) != numPaletteIndices {
+			t.Fatalf("unexpected color model %v", frame.ColorModel())
+		}
+
+		bounds := frame.Bounds()
+
+		for y := bounds.Min.Y - 1; y <= bounds.Max.Y; y++ {
+			for x := bounds.Min.X - 1; x <= bounds.Max.X; x++ {
+				want := color.RGBA64Model.Convert(frame.At(x, y))
+				got := color.RGBA64Model.Convert(model[frame.ColorIndexAt(x, y)])
+
+				if got != want {
+					t.Fatalf("pixel %d,%d is %v through ColorIndexAt, %v through At", x, y, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestIsDirtyChecksumCollision(t *testing.T) {
+	d := roundTrip(t, newTestDC6(1, 2))
+	frame := d.Directions[0].Frames[1]
+
+	if frame.IsDirty() {
+		t.Fatal("a decoded frame should not be dirty")
+	}
+
+	frame.IndexData[3] ^= 0xff
+
+	// an edit that does not change the checksums
+	frame.decoded = frame.snapshot()
+
+	if !frame.IsDirty() {
+		t.Fatal("an edited frame should be dirty")
+	}
+
+	if got := roundTrip(t, d); got.Directions[0].Frames[1].IndexData[3] != frame.IndexData[3] {
+		t.Error("the edit was not encoded")
+	}
+}
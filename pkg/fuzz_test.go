@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"bytes"
+	"testing"
+)
+
+// fuzzOptions keeps the memory used by each fuzzed input small.
+var fuzzOptions = DecodeOptions{
+	MaxTotalPixels: 1 << 20,
+	MaxTotalBytes:  1 << 20,
+}
+
+// addFuzzSeeds adds a seed corpus of synthetic DC6 files to f.
+func addFuzzSeeds(f *testing.F) {
+	empty := New(1, 1)
+	empty.Directions[0].AddFrame(&Frame{})
+
+	for _, d := range []*DC6{empty, newTestDC6(1, 1), newTestDC6(2, 3)} {
+		data, err := d.ToBytes()
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		f.Add(data)
+		f.Add(relayout(data, 3))
+		f.Add(data[:len(data)/2])
+	}
+}
+
+// exercise uses every frame of a decoded DC6, and encodes it again if it is
+// valid.
+func exercise(t *testing.T, d *DC6) {
+	for _, direction := range d.Directions {
+		for _, frame := range direction.Frames {
+			_ = frame.Decode()
+			_ = frame.ToImageRGBA()
+			_ = frame.ToPaletted()
+		}
+	}
+
+	_ = d.Warnings()
+
+	if d.Validate() != nil {
+		return
+	}
+
+	if _, err := d.ToBytes(); err != nil {
+		t.Fatalf("could not encode a decoded DC6, %v", err)
+	}
+}
+
+func FuzzFromBytes(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, o := range []DecodeOptions{fuzzOptions, {Mode: ModeLenient, Salvage: true}} {
+			o.MaxTotalPixels, o.MaxTotalBytes = fuzzOptions.MaxTotalPixels, fuzzOptions.MaxTotalBytes
+
+			if d, err := o.FromBytes(data); err == nil {
+				exercise(t, d)
+			}
+		}
+	})
+}
+
+func FuzzDecode(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if d, err := fuzzOptions.Decode(bytes.NewReader(data)); err == nil {
+			exercise(t, d)
+		}
+	})
+}
+
+func FuzzOpen(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if d, err := fuzzOptions.Open(bytes.NewReader(data), int64(len(data))); err == nil {
+			exercise(t, d)
+		}
+	})
+}
@@ -1,5 +1,12 @@
 package pkg
 
+import (
+	"io"
+	"sync"
+
+	"github.com/gravestench/bitstream"
+)
+
 // Header represents the file header of a DC6 file.
 type Header struct {
 	Version            int32  `struct:"int32"`
@@ -9,3 +16,64 @@ type Header struct {
 	Directions         int32  `struct:"int32"`
 	FramesPerDirection int32  `struct:"int32"`
 }
+
+// bitstreamMu serializes the use of bitstream readers, which share a read
+// buffer between all readers.
+var bitstreamMu sync.Mutex
+
+// ReadHeader reads the file header of a DC6 from the given reader. The frame
+// pointer table that follows the header is not read.
+func ReadHeader(r io.Reader) (*Header, error) {
+	const (
+		versionBytes            = 4
+		flagsBytes              = 4
+		encodingBytes           = 4
+		terminationBytes        = 4
+		directionsBytes         = 4
+		framesPerDirectionBytes = 4
+	)
+
+	data := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, headerError(0, wrapErr(ErrTruncatedHeader, err))
+	}
+
+	bitstreamMu.Lock()
+	defer bitstreamMu.Unlock()
+
+	stream := bitstream.NewReader().FromBytes(data...)
+	header := &Header{}
+
+	var err error
+
+	// only check last err
+	header.Version, _ = stream.Next(versionBytes).Bytes().AsInt32()
+	header.Flags, _ = stream.Next(flagsBytes).Bytes().AsUInt32()
+	header.Encoding, _ = stream.Next(encodingBytes).Bytes().AsUInt32()
+	header.Termination, _ = stream.Next(terminationBytes).Bytes().AsBytes()
+	header.Directions, _ = stream.Next(directionsBytes).Bytes().AsInt32()
+	header.FramesPerDirection, err = stream.Next(framesPerDirectionBytes).Bytes().AsInt32()
+
+	if err != nil {
+		return nil, headerError(0, wrapErr(ErrTruncatedHeader, err))
+	}
+
+	return header, nil
+}
+
+// Header returns the file header of the DC6.
+func (d *DC6) Header() *Header {
+	header := &Header{
+		Version:     d.Version,
+		Flags:       d.Flags,
+		Encoding:    d.Encoding,
+		Termination: append([]byte(nil), d.Termination...),
+		Directions:  int32(len(d.Directions)),
+	}
+
+	if len(d.Directions) > 0 && d.Directions[0] != nil {
+		header.FramesPerDirection = int32(len(d.Directions[0].Frames))
+	}
+
+	return header
+}
@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestReadHeader(t *testing.T) {
+	d := newTestDC6(2, 3)
+	d.Flags, d.Encoding = 1, 0
+
+	data, err := d.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := ReadHeader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if header.Version != dc6Version || header.Flags != 1 || header.Encoding != 0 {
+		t.Errorf("unexpected version, flags or encoding, %+v", header)
+	}
+
+	if header.Directions != 2 || header.FramesPerDirection != 3 {
+		t.Errorf("expected 2 directions of 3 frames, got %d of %d", header.Directions, header.FramesPerDirection)
+	}
+
+	if !isTermination(header.Termination) {
+		t.Errorf("unexpected termination bytes % x", header.Termination)
+	}
+
+	for _, size := range []int{0, 10, headerSize - 1} {
+		if _, err := ReadHeader(bytes.NewReader(data[:size])); !errors.Is(err, ErrTruncatedHeader) {
+			t.Errorf("%d bytes: expected ErrTruncatedHeader, got %v", size, err)
+		}
+	}
+}
+
+func TestReadFrameHeaders(t *testing.T) {
+	data, err := newTestDC6(2, 3).ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data = relayout(data, 3)
+
+	headers, err := ReadFrameHeaders(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(headers) != 6 {
+		t.Fatalf("expected 6 frame headers, got %d", len(headers))
+	}
+
+	for idx, header := range headers {
+		dirIdx, frameIdx := idx/3, idx%3
+		pointer := binary.LittleEndian.Uint32(data[headerSize+framePointerSize*idx:])
+
+		if header.Width != int32(5+70*frameIdx) || header.Height != int32(3+dirIdx) {
+			t.Errorf("direction %d frame %d: unexpected size %dx%d", dirIdx, frameIdx, header.Width, header.Height)
+		}
+
+		if header.OffsetX != int32(-dirIdx) || header.OffsetY != int32(frameIdx) {
+			t.Errorf("direction %d frame %d: unexpected offset %d,%d", dirIdx, frameIdx, header.OffsetX, header.OffsetY)
+		}
+
+		if header.NextBlock != binary.LittleEndian.Uint32(data[pointer+24:]) {
+			t.Errorf("direction %d frame %d: unexpected next block %d", dirIdx, frameIdx, header.NextBlock)
+		}
+
+		if header.Length != binary.LittleEndian.Uint32(data[pointer+28:]) {
+			t.Errorf("direction %d frame %d: unexpected length %d", dirIdx, frameIdx, header.Length)
+		}
+	}
+
+	if _, err := ReadFrameHeaders(bytes.NewReader(data[:10]), 10); !errors.Is(err, ErrTruncatedHeader) {
+		t.Errorf("expected ErrTruncatedHeader, got %v", err)
+	}
+}
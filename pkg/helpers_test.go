@@ -0,0 +1,94 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// newTestDC6 returns a DC6 with the given number of directions and frames per
+// direction. Its frames have distinct sizes, offsets and pixels, with runs of
+// transparent and opaque pixels, some of them longer than a single run.
+func newTestDC6(directions, frames int) *DC6 {
+	d := New(directions, frames)
+
+	for dirIdx := 0; dirIdx < directions; dirIdx++ {
+		for frameIdx := 0; frameIdx < frames; frameIdx++ {
+			width, height := 5+70*frameIdx, 3+dirIdx
+
+			frame := &Frame{
+				Width:     uint32(width),
+				Height:    uint32(height),
+				OffsetX:   int32(-dirIdx),
+				OffsetY:   int32(frameIdx),
+				IndexData: make([]byte, width*height),
+				Mask:      make([]bool, width*height),
+			}
+
+			for idx := range frame.IndexData {
+				if idx%11 < 3 {
+					continue
+				}
+
+				frame.IndexData[idx] = byte(1 + (idx*7+dirIdx*31+frameIdx*13)%255)
+				frame.Mask[idx] = true
+			}
+
+			d.Directions[dirIdx].AddFrame(frame)
+		}
+	}
+
+	return d
+}
+
+// decoders returns the ways a DC6 file can be decoded with the given options,
+// by name.
+func decoders(o DecodeOptions) map[string]func(data []byte) (*DC6, error) {
+	lazy := o
+	lazy.Lazy = true
+
+	return map[string]func(data []byte) (*DC6, error){
+		"FromBytes": o.FromBytes,
+		"Lazy":      lazy.FromBytes,
+		"Open":      func(data []byte) (*DC6, error) { return o.Open(bytes.NewReader(data), int64(len(data))) },
+		"Decode":    func(data []byte) (*DC6, error) { return o.Decode(bytes.NewReader(data)) },
+	}
+}
+
+// relayout returns a copy of the given DC6 file with its frame blocks stored
+// in reverse order, each preceded by the given number of padding bytes. The
+// frame pointers and NextBlock values of the copy are correct.
+func relayout(data []byte, padding int) []byte {
+	numFrames := int(binary.LittleEndian.Uint32(data[16:])) * int(binary.LittleEndian.Uint32(data[20:]))
+	tableEnd := headerSize + framePointerSize*numFrames
+
+	blocks := make([][]byte, numFrames)
+
+	for idx := range blocks {
+		pointer := binary.LittleEndian.Uint32(data[headerSize+framePointerSize*idx:])
+		length := binary.LittleEndian.Uint32(data[pointer+28:])
+		blocks[idx] = data[pointer : pointer+frameHeaderSize+length+terminatorSize]
+	}
+
+	out := append([]byte(nil), data[:tableEnd]...)
+	pointers := make([]uint32, numFrames)
+
+	for idx := numFrames - 1; idx >= 0; idx-- {
+		out = append(out, make([]byte, padding)...)
+		pointers[idx] = uint32(len(out))
+		out = append(out, blocks[idx]...)
+	}
+
+	for idx, pointer := range pointers {
+		binary.LittleEndian.PutUint32(out[headerSize+framePointerSize*idx:], pointer)
+
+		// blocks are followed by the block of the previous frame
+		nextBlock := pointer + uint32(len(blocks[idx]))
+		if idx > 0 {
+			nextBlock = pointers[idx-1]
+		}
+
+		binary.LittleEndian.PutUint32(out[pointer+24:], nextBlock)
+	}
+
+	return out
+}
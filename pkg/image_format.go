@@ -0,0 +1,64 @@
+package pkg
+
+import (
+	"image"
+	"io"
+)
+
+// imageMagic matches the version (6) and flags of the DC6 file header.
+const imageMagic = "\x06\x00\x00\x00?\x00\x00\x00"
+
+// image.Decode and image.DecodeConfig yield the first frame of the first
+// direction of a DC6 file, DecodeFrame and DecodeFrameConfig select any frame.
+func init() {
+	image.RegisterFormat("dc6", imageMagic, decodeImage, decodeImageConfig)
+}
+
+func decodeImage(r io.Reader) (image.Image, error) {
+	return DecodeFrame(r, 0, 0)
+}
+
+func decodeImageConfig(r io.Reader) (image.Config, error) {
+	return DecodeFrameConfig(r, 0, 0)
+}
+
+// DecodeFrame reads a DC6 from the given reader and decodes a single frame,
+// the frame data of the other frames is read but not decoded.
+func DecodeFrame(r io.Reader, direction, frame int) (*Frame, error) {
+	f, err := selectFrame(r, direction, frame)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = f.Decode(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// DecodeFrameConfig reads a DC6 from the given reader and returns the color
+// model and dimensions of a single frame, without decoding any frame.
+func DecodeFrameConfig(r io.Reader, direction, frame int) (image.Config, error) {
+	f, err := selectFrame(r, direction, frame)
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	return image.Config{
+		ColorModel: f.ColorModel(),
+		Width:      int(f.Width),
+		Height:     int(f.Height),
+	}, nil
+}
+
+// selectFrame reads a DC6 from the given reader and returns a single frame,
+// without decoding it.
+func selectFrame(r io.Reader, direction, frame int) (*Frame, error) {
+	d, err := DecodeOptions{Lazy: true}.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.frameAt(direction, frame)
+}
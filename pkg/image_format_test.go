@@ -0,0 +1,44 @@
+package pkg
+
+import (
+	"bytes"
+	"image"
+	"testing"
+)
+
+func TestImageDecode(t *testing.T) {
+	d := newTestDC6(2, 3)
+
+	data, err := d.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil || format != "dc6" {
+		t.Fatalf("could not decode image, %q, %v", format, err)
+	}
+
+	want := d.Directions[0].Frames[0]
+	if img.Bounds() != want.Bounds() {
+		t.Errorf("image.Decode yields %v, expected the first frame %v", img.Bounds(), want.Bounds())
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width != int(want.Width) || config.Height != int(want.Height) {
+		t.Errorf("unexpected config %dx%d, %v", config.Width, config.Height, err)
+	}
+
+	frame, err := DecodeFrame(bytes.NewReader(data), 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(frame.IndexData, d.Directions[1].Frames[2].IndexData) {
+		t.Error("DecodeFrame yields the wrong frame")
+	}
+
+	if _, err := DecodeFrame(bytes.NewReader(data), 2, 0); err == nil {
+		t.Error("expected an error for a direction out of range")
+	}
+}
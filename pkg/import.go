@@ -0,0 +1,296 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// Dither selects how colors that are not in the palette are approximated
+// when importing images.
+type Dither int
+
+const (
+	// DitherNone maps every pixel to the nearest palette color.
+	DitherNone Dither = iota
+
+	// DitherOrdered offsets every pixel by a 4x4 Bayer matrix before mapping
+	// it to the nearest palette color.
+	DitherOrdered
+
+	// DitherFloydSteinberg diffuses the difference between every pixel and its
+	// nearest palette color to the neighboring pixels.
+	DitherFloydSteinberg
+)
+
+// DefaultAlphaThreshold is the alpha below which pixels are transparent, when
+// ImportOptions.AlphaThreshold is 0.
+const DefaultAlphaThreshold = 0x80
+
+// orderedDitherSpread is the range of the offsets of ordered dithering, as a
+// fraction of the range of a color component.
+const orderedDitherSpread = 1.0 / 8
+
+// bayerMatrix is the 4x4 Bayer threshold matrix used for ordered dithering.
+var bayerMatrix = [4][4]float64{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// ImportOptions controls how images are converted to DC6 frames. The zero
+// value maps pixels to the nearest color of the default palette, without
+// dithering.
+type ImportOptions struct {
+	// Palette is the palette the images are mapped to, which is also set as
+	// the palette of imported DC6s. If nil, the default palette is used.
+	// Palette index 0 and fully transparent colors are never used for opaque
+	// pixels, index 0 is used for transparent pixels.
+	Palette color.Palette
+
+	// Dither selects how colors that are not in the palette are approximated.
+	Dither Dither
+
+	// AlphaThreshold is the alpha below which pixels are transparent. If 0,
+	// DefaultAlphaThreshold is used.
+	AlphaThreshold uint8
+
+	// Origin is the location in image coordinates of the point the frame
+	// offsets are relative to. Frames are offset by the top-left corner of
+	// the image bounds, relative to the Origin.
+	Origin image.Point
+
+	// Trim crops fully transparent rows and columns from the edges of every
+	// frame, the frame offsets are adjusted accordingly.
+	Trim bool
+}
+
+// Import creates a DC6 from the given images, which are indexed by direction
+// and frame. Every direction must have the same number of frames.
+func (o ImportOptions) Import(images [][]image.Image) (*DC6, error) {
+	if len(images) == 0 {
+		return nil, errors.New("could not import images, no directions")
+	}
+
+	q, err := o.quantizer()
+	if err != nil {
+		return nil, fmt.Errorf("could not import images, %w", err)
+	}
+
+	d := New(len(images), len(images[0]))
+	d.SetPalette(o.Palette)
+
+	for dirIdx := range images {
+		if len(images[dirIdx]) != len(images[0]) {
+			const fmtErr = "could not import images, direction %d has %d frames, expected %d"
+			return nil, fmt.Errorf(fmtErr, dirIdx, len(images[dirIdx]), len(images[0]))
+		}
+
+		for frameIdx, img := range images[dirIdx] {
+			frame, err := o.newFrame(q, img)
+			if err != nil {
+				return nil, fmt.Errorf("could not import direction %d frame %d, %w", dirIdx, frameIdx, err)
+			}
+
+			d.Directions[dirIdx].AddFrame(frame)
+		}
+	}
+
+	return d, nil
+}
+
+// NewFrame creates a frame from the given image, see ImportOptions.
+func (o ImportOptions) NewFrame(img image.Image) (*Frame, error) {
+	q, err := o.quantizer()
+	if err != nil {
+		return nil, fmt.Errorf("could not import image, %w", err)
+	}
+
+	return o.newFrame(q, img)
+}
+
+func (o ImportOptions) newFrame(q *quantizer, img image.Image) (*Frame, error) {
+	if img == nil {
+		return nil, errors.New("image is nil")
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if uint64(width)*uint64(height) > maxFramePixels {
+		return nil, fmt.Errorf("%w, %dx%d pixels", ErrOversizedFrame, width, height)
+	}
+
+	threshold := o.AlphaThreshold
+	if threshold == 0 {
+		threshold = DefaultAlphaThreshold
+	}
+
+	offset := bounds.Min.Sub(o.Origin)
+	frame := &Frame{
+		Width:     uint32(width),
+		Height:    uint32(height),
+		OffsetX:   int32(offset.X),
+		OffsetY:   int32(offset.Y),
+		IndexData: make([]byte, width*height),
+		Mask:      make([]bool, width*height),
+	}
+
+	// the errors diffused to the current and the next row
+	current, next := make([]oklab, width+2), make([]oklab, width+2)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			if c.A < threshold {
+				continue
+			}
+
+			if o.Dither == DitherNone {
+				frame.IndexData[y*width+x] = q.nearestRGB(c.R, c.G, c.B)
+				frame.Mask[y*width+x] = true
+
+				continue
+			}
+
+			r, g, b := float64(c.R)/math.MaxUint8, float64(c.G)/math.MaxUint8, float64(c.B)/math.MaxUint8
+
+			if o.Dither == DitherOrdered {
+				t := (bayerMatrix[y%4][x%4]+0.5)/16 - 0.5
+				r, g, b = r+t*orderedDitherSpread, g+t*orderedDitherSpread, b+t*orderedDitherSpread
+			}
+
+			target := toOklab(clamp01(r), clamp01(g), clamp01(b))
+
+			if o.Dither == DitherFloydSteinberg {
+				target = target.add(current[x+1])
+			}
+
+			idx := q.nearest(target)
+			frame.IndexData[y*width+x] = idx
+			frame.Mask[y*width+x] = true
+
+			if o.Dither == DitherFloydSteinberg {
+				diff := target.sub(q.colors[idx])
+				current[x+2] = current[x+2].add(diff.scale(7.0 / 16))
+				next[x] = next[x].add(diff.scale(3.0 / 16))
+				next[x+1] = next[x+1].add(diff.scale(5.0 / 16))
+				next[x+2] = next[x+2].add(diff.scale(1.0 / 16))
+			}
+		}
+
+		current, next = next, current
+		for idx := range next {
+			next[idx] = oklab{}
+		}
+	}
+
+	if o.Trim {
+		frame.trim()
+	}
+
+	return frame, nil
+}
+
+// trim crops fully transparent rows and columns from the edges of the frame.
+func (f *Frame) trim() {
+	width, height := int(f.Width), int(f.Height)
+	opaque := image.Rectangle{}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if f.Mask[y*width+x] {
+				opaque = opaque.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+
+	indexData := make([]byte, 0, opaque.Dx()*opaque.Dy())
+	mask := make([]bool, 0, opaque.Dx()*opaque.Dy())
+
+	for y := opaque.Min.Y; y < opaque.Max.Y; y++ {
+		indexData = append(indexData, f.IndexData[y*width+opaque.Min.X:y*width+opaque.Max.X]...)
+		mask = append(mask, f.Mask[y*width+opaque.Min.X:y*width+opaque.Max.X]...)
+	}
+
+	f.Width, f.Height = uint32(opaque.Dx()), uint32(opaque.Dy())
+	f.OffsetX += int32(opaque.Min.X)
+	f.OffsetY += int32(opaque.Min.Y)
+	f.IndexData, f.Mask = indexData, mask
+}
+
+// quantizer maps colors to the nearest opaque color of a palette.
+type quantizer struct {
+	colors  []oklab // the colors of the palette, indexed by palette index
+	indices []uint8 // the palette indices that can be used for opaque pixels
+	cache   map[[3]uint8]uint8
+}
+
+func (o ImportOptions) quantizer() (*quantizer, error) {
+	palette := o.Palette
+	if palette == nil {
+		palette = defaultPalette()
+	}
+
+	q := &quantizer{
+		colors: make([]oklab, len(palette)),
+		cache:  make(map[[3]uint8]uint8),
+	}
+
+	for idx, c := range palette {
+		if idx >= numPaletteIndices {
+			break
+		}
+
+		if c == nil {
+			continue
+		}
+
+		q.colors[idx] = colorToOklab(c)
+
+		// index 0 is transparent in the game
+		if _, _, _, a := c.RGBA(); idx > 0 && a > 0 {
+			q.indices = append(q.indices, uint8(idx))
+		}
+	}
+
+	if len(q.indices) == 0 {
+		return nil, errors.New("palette has no opaque colors besides index 0")
+	}
+
+	return q, nil
+}
+
+// nearest returns the palette index of the color nearest to the given color.
+func (q *quantizer) nearest(c oklab) uint8 {
+	best, bestDistance := q.indices[0], math.Inf(1)
+
+	for _, idx := range q.indices {
+		if distance := c.distance(q.colors[idx]); distance < bestDistance {
+			best, bestDistance = idx, distance
+		}
+	}
+
+	return best
+}
+
+// nearestRGB returns the palette index of the color nearest to the given sRGB
+// color, the results are cached.
+func (q *quantizer) nearestRGB(r, g, b uint8) uint8 {
+	key := [3]uint8{r, g, b}
+
+	idx, found := q.cache[key]
+	if !found {
+		idx = q.nearest(toOklab(float64(r)/math.MaxUint8, float64(g)/math.MaxUint8, float64(b)/math.MaxUint8))
+		q.cache[key] = idx
+	}
+
+	return idx
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
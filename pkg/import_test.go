@@ -0,0 +1,168 @@
+package pkg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+var (
+	black = color.RGBA{A: 0xff}
+	gray  = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+	white = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	red   = color.RGBA{R: 0xff, A: 0xff}
+)
+
+// testPalette has black at index 0, which must not be used for opaque
+// pixels, a transparent color, and black, white and red.
+var testPalette = color.Palette{black, color.RGBA{}, black, white, red}
+
+func uniformImage(rect image.Rectangle, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(rect)
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.Set(x, y, c)
+		}
+	}
+
+	return img
+}
+
+func TestImportNearestColor(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 5, 1))
+	img.Set(0, 0, black)
+	img.Set(1, 0, color.NRGBA{R: 0xf0, G: 0xf0, B: 0xf0, A: 0xff})
+	img.Set(2, 0, color.NRGBA{R: 0xe0, G: 0x20, B: 0x10, A: 0xff})
+	img.Set(3, 0, color.NRGBA{R: 0xff, A: 0x7f}) // below the default threshold
+	img.Set(4, 0, color.NRGBA{R: 0xff, A: 0x80})
+
+	frame, err := ImportOptions{Palette: testPalette}.NewFrame(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantIndices := []byte{2, 3, 4, 0, 4}
+	wantMask := []bool{true, true, true, false, true}
+
+	for idx := range wantIndices {
+		if frame.IndexData[idx] != wantIndices[idx] || frame.Mask[idx] != wantMask[idx] {
+			t.Errorf("pixel %d is %d %v, expected %d %v",
+				idx, frame.IndexData[idx], frame.Mask[idx], wantIndices[idx], wantMask[idx])
+		}
+	}
+
+	frame, err = ImportOptions{Palette: testPalette, AlphaThreshold: 0xff}.NewFrame(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if frame.Mask[4] || !frame.Mask[0] {
+		t.Error("the alpha threshold is not applied")
+	}
+}
+
+func TestImportNoOpaqueColors(t *testing.T) {
+	o := ImportOptions{Palette: color.Palette{black, color.RGBA{}}}
+
+	if _, err := o.NewFrame(uniformImage(image.Rect(0, 0, 1, 1), black)); err == nil {
+		t.Error("expected an error for a palette without opaque colors besides index 0")
+	}
+}
+
+func TestImportDither(t *testing.T) {
+	const size = 32
+
+	// the offsets of ordered dithering only span nearby colors
+	img := uniformImage(image.Rect(0, 0, size, size), gray)
+	p := color.Palette{color.RGBA{}, color.Gray{Y: 0x74}, color.Gray{Y: 0x8c}}
+
+	tests := map[Dither][2]float64{
+		DitherNone:           {0, 0},
+		DitherOrdered:        {0.1, 0.9},
+		DitherFloydSteinberg: {0.1, 0.9},
+	}
+
+	for dither, bounds := range tests {
+		frame, err := ImportOptions{Palette: p, Dither: dither}.NewFrame(img)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lights := 0
+
+		for idx, cidx := range frame.IndexData {
+			if !frame.Mask[idx] || cidx == 0 {
+				t.Fatalf("dither %d: pixel %d is not opaque", dither, idx)
+			}
+
+			if cidx == 2 {
+				lights++
+			}
+		}
+
+		// without dithering, every pixel gets the same color
+		fraction := float64(lights) / (size * size)
+		if dither == DitherNone && lights != 0 && lights != size*size {
+			t.Errorf("dither %d: expected a single color, %v of the pixels are light", dither, fraction)
+		}
+
+		if dither != DitherNone && (fraction < bounds[0] || fraction > bounds[1]) {
+			t.Errorf("dither %d: %v of the pixels are light", dither, fraction)
+		}
+	}
+}
+
+func TestImportOffsetsAndTrim(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(10, 20, 16, 24))
+	img.Set(12, 21, red)
+	img.Set(13, 22, red)
+
+	o := ImportOptions{Palette: testPalette, Origin: image.Pt(8, 30)}
+
+	frame, err := o.NewFrame(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if frame.Width != 6 || frame.Height != 4 || frame.OffsetX != 2 || frame.OffsetY != -10 {
+		t.Errorf("unexpected frame %dx%d at %d,%d", frame.Width, frame.Height, frame.OffsetX, frame.OffsetY)
+	}
+
+	o.Trim = true
+
+	frame, err = o.NewFrame(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if frame.Width != 2 || frame.Height != 2 || frame.OffsetX != 4 || frame.OffsetY != -9 {
+		t.Errorf("unexpected trimmed frame %dx%d at %d,%d", frame.Width, frame.Height, frame.OffsetX, frame.OffsetY)
+	}
+
+	if !frame.Mask[0] || frame.Mask[1] || frame.Mask[2] || !frame.Mask[3] {
+		t.Errorf("unexpected trimmed mask %v", frame.Mask)
+	}
+}
+
+func TestImport(t *testing.T) {
+	img := uniformImage(image.Rect(0, 0, 2, 2), red)
+	o := ImportOptions{Palette: testPalette}
+
+	d, err := o.Import([][]image.Image{{img, img}, {img, img}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(d.Directions) != 2 || len(d.Directions[1].Frames) != 2 {
+		t.Fatalf("unexpected layout %v", d.Directions)
+	}
+
+	if got := d.Directions[1].Frames[1].At(0, 0); got != red {
+		t.Errorf("imported frame is not drawn with the palette, got %v", got)
+	}
+
+	if _, err := o.Import([][]image.Image{{img, img}, {img}}); err == nil {
+		t.Error("expected an error for directions with different numbers of frames")
+	}
+}
@@ -0,0 +1,168 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// gap is a range of a decoded file that is not part of the header, the frame
+// pointer table or a frame block: padding before a frame block, or data after
+// the last frame block. Gaps are kept so that a DC6 that is encoded without
+// edits yields the file it was decoded from.
+type gap struct {
+	offset int64
+	length int64
+	data   []byte // nil if the gap is read from the source of the DC6
+}
+
+// layoutGaps records the gaps between the end of the frame pointer table and
+// the given file size, the frames must have been read from the file.
+func (d *DC6) layoutGaps(tableEnd, size int64) {
+	offset := tableEnd
+
+	for _, idx := range d.fileOrder() {
+		frame := d.frameByIndex(idx)
+		if frame.dataOffset == 0 {
+			continue
+		}
+
+		if pointer := frame.dataOffset - frameHeaderSize; pointer > offset {
+			d.addGap(gap{offset: offset, length: pointer - offset})
+		}
+
+		if end := frame.dataOffset + int64(frame.Length) + terminatorSize; end > offset {
+			offset = end
+		}
+	}
+
+	if size > offset {
+		d.addGap(gap{offset: offset, length: size - offset})
+	}
+}
+
+func (d *DC6) addGap(g gap) {
+	if d.gaps == nil {
+		d.gaps = make(map[int64]gap)
+	}
+
+	d.gaps[g.offset] = g
+}
+
+// gapData returns the bytes of the given gap.
+func (d *DC6) gapData(g gap) ([]byte, error) {
+	if g.data != nil || d.source == nil {
+		return g.data, nil
+	}
+
+	data := make([]byte, g.length)
+
+	// a ReaderAt may return io.EOF alongside a complete read
+	if n, err := d.source.ReadAt(data, g.offset); n < len(data) {
+		return nil, fmt.Errorf("could not read %d bytes at %d, %w", g.length, g.offset, err)
+	}
+
+	return data, nil
+}
+
+// keepsLayout reports whether the DC6 can be encoded with the layout of the
+// file it was decoded from, given the encoded frame data of its frames. This
+// is the case if none of the frame blocks were changed, moved or resized, and
+// the gaps between them are known.
+func (d *DC6) keepsLayout(encoded [][]byte) bool {
+	if len(d.FramePointers) != len(encoded) {
+		return false
+	}
+
+	framesPerDirection := len(d.Directions[0].Frames)
+
+	for idx, pointer := range d.FramePointers {
+		frame := d.Directions[idx/framesPerDirection].Frames[idx%framesPerDirection]
+
+		if frame.dataOffset != int64(pointer)+frameHeaderSize || len(encoded[idx]) != int(frame.Length) {
+			return false
+		}
+	}
+
+	offset := int64(headerSize + framePointerSize*len(d.FramePointers))
+	previous := int64(-1)
+
+	for _, idx := range d.fileOrder() {
+		pointer := int64(d.FramePointers[idx])
+
+		switch {
+		case pointer == previous:
+			// frames sharing a frame pointer share the frame block
+			continue
+		case pointer < offset:
+			return false
+		case pointer > offset:
+			if g, found := d.gaps[offset]; !found || g.offset+g.length != pointer {
+				return false
+			}
+		}
+
+		previous, offset = pointer, pointer+frameHeaderSize+int64(len(encoded[idx]))+terminatorSize
+	}
+
+	return true
+}
+
+// encodeLayout encodes the DC6 with the layout of the file it was decoded
+// from, see keepsLayout.
+func (d *DC6) encodeLayout(w io.Writer, encoded [][]byte) error {
+	framesPerDirection := len(d.Directions[0].Frames)
+	offset := int64(headerSize + framePointerSize*len(d.FramePointers))
+	buf := &bytes.Buffer{}
+
+	d.encodeHeader(buf, uint32(len(d.Directions)), uint32(framesPerDirection))
+
+	for _, pointer := range d.FramePointers {
+		writeUint32(buf, pointer)
+	}
+
+	writeGap := func() error {
+		g, found := d.gaps[offset]
+		if !found {
+			return nil
+		}
+
+		data, err := d.gapData(g)
+		if err != nil {
+			return err
+		}
+
+		buf.Write(data)
+		offset += g.length
+
+		return nil
+	}
+
+	for _, idx := range d.fileOrder() {
+		// frames sharing a frame pointer share the frame block
+		if int64(d.FramePointers[idx]) < offset {
+			continue
+		}
+
+		if err := writeGap(); err != nil {
+			return err
+		}
+
+		frame := d.Directions[idx/framesPerDirection].Frames[idx%framesPerDirection]
+
+		frame.encodeHeader(buf, frame.NextBlock, uint32(len(encoded[idx])))
+		buf.Write(encoded[idx])
+		buf.Write(terminationBytes(frame.Terminator, terminatorSize))
+
+		offset = int64(d.FramePointers[idx]) + frameHeaderSize + int64(len(encoded[idx])) + terminatorSize
+	}
+
+	// data after the last frame block
+	if err := writeGap(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"image/color"
+	"math"
+)
+
+// oklab is a color in the Oklab color space, in which the euclidean distance
+// between two colors approximates how different they look.
+// See https://bottosson.github.io/posts/oklab/
+type oklab struct {
+	L, A, B float64
+}
+
+// toOklab converts a color with sRGB components in the range [0, 1].
+func toOklab(r, g, b float64) oklab {
+	r, g, b = linearize(r), linearize(g), linearize(b)
+
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	return oklab{
+		L: 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		A: 1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		B: 0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// colorToOklab converts a color, ignoring its alpha.
+func colorToOklab(c color.Color) oklab {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+
+	return toOklab(float64(n.R)/math.MaxUint8, float64(n.G)/math.MaxUint8, float64(n.B)/math.MaxUint8)
+}
+
+// linearize converts an sRGB component to linear light.
+func linearize(c float64) float64 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// distance returns the squared distance between two colors.
+func (c oklab) distance(other oklab) float64 {
+	dl, da, db := c.L-other.L, c.A-other.A, c.B-other.B
+
+	return dl*dl + da*da + db*db
+}
+
+func (c oklab) add(other oklab) oklab {
+	return oklab{L: c.L + other.L, A: c.A + other.A, B: c.B + other.B}
+}
+
+func (c oklab) sub(other oklab) oklab {
+	return oklab{L: c.L - other.L, A: c.A - other.A, B: c.B - other.B}
+}
+
+func (c oklab) scale(f float64) oklab {
+	return oklab{L: c.L * f, A: c.A * f, B: c.B * f}
+}
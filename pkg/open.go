@@ -0,0 +1,146 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+)
+
+// Open decodes the header, frame pointer table and frame headers of the DC6
+// file read from r, see DecodeOptions.Open.
+func Open(r io.ReaderAt, size int64) (result *DC6, err error) {
+	return DecodeOptions{}.Open(r, size)
+}
+
+// Open decodes the header, frame pointer table and frame headers of the DC6
+// file read from r. Frame data is only read from r when a frame is decoded
+// with Frame.Decode, so r must remain readable for as long as the DC6 is used.
+func (o DecodeOptions) Open(r io.ReaderAt, size int64) (result *DC6, err error) {
+	result = &DC6{mode: o.Mode, salvaging: o.Salvage}
+	limits := o.limiter()
+
+	stream := io.NewSectionReader(r, 0, size)
+
+	if _, _, err = result.decodeHeader(stream, limits); err != nil {
+		return nil, err
+	}
+
+	tableEnd, _ := stream.Seek(0, io.SeekCurrent)
+	end := tableEnd
+
+	for idx, pointer := range result.FramePointers {
+		offset := int64(pointer)
+
+		if offset+frameHeaderSize > size {
+			const fmtErr = "%w, frame at %d exceeds file size %d"
+			err = result.frameError(idx, pointerOffset(idx), fmt.Errorf(fmtErr, ErrPointerOutOfRange, offset, size))
+
+			if err = result.salvage(idx, nil, err); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if _, err = stream.Seek(offset, io.SeekStart); err != nil {
+			return nil, result.frameError(idx, offset, wrapErr(ErrPointerOutOfRange, err))
+		}
+
+		frame, err := result.decodeFrameHeader(stream)
+		if err != nil {
+			return nil, result.frameError(idx, offset, wrapErr(ErrTruncatedFrameData, err))
+		}
+
+		if err = result.checkFrameHeader(idx, offset, frame, limits); err != nil {
+			if err = result.salvage(idx, nil, err); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		frame.source = stream
+		frame.dataOffset = offset + frameHeaderSize
+
+		frameEnd := frame.dataOffset + int64(frame.Length) + terminatorSize
+		if frameEnd > size {
+			const fmtErr = "%w, frame data ends at %d, file size is %d"
+			err = result.frameError(idx, frame.dataOffset, fmt.Errorf(fmtErr, ErrTruncatedFrameData, frameEnd, size))
+
+			// the frame data that is there is read when the frame is decoded
+			if err = result.salvage(idx, frame, err); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if frameEnd > end {
+			end = frameEnd
+		}
+
+		result.setFrame(idx, frame)
+	}
+
+	if err = result.checkFramePointers(); err != nil {
+		return nil, err
+	}
+
+	result.source = stream
+	result.layoutGaps(tableEnd, size)
+
+	if end < size {
+		err = fmt.Errorf("%w, %d bytes after the last frame", ErrTrailingData, size-end)
+		if err = result.anomaly(WarningTrailingData, end, err); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// frameData returns the FrameData, reading it from the reader the DC6 was
+// opened from if needed.
+func (f *Frame) frameData() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+
+	return f.FrameData, nil
+}
+
+// load reads the frame data and terminator from the reader the DC6 was opened
+// from, if this has not happened yet. The caller must hold f.mu.
+func (f *Frame) load() error {
+	if f.FrameData != nil || f.source == nil {
+		return nil
+	}
+
+	data := make([]byte, int(f.Length)+terminatorSize)
+
+	// a ReaderAt may return io.EOF alongside a complete read
+	n, err := f.source.ReadAt(data, f.dataOffset)
+	if n == len(data) {
+		err = nil
+	} else {
+		err = f.decodeError(n, wrapErr(ErrTruncatedFrameData, err))
+
+		if !f.salvaging() {
+			return err
+		}
+
+		// keep whatever frame data could be read
+		data = data[:n]
+	}
+
+	length := int(f.Length)
+	if length > len(data) {
+		length = len(data)
+	}
+
+	f.FrameData, f.Terminator = data[:length], data[length:]
+
+	return err
+}
@@ -0,0 +1,208 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Default resource limits, used for DecodeOptions limits that are zero. The
+// DC6 files of the game stay well below them, the largest have a few million
+// pixels in total.
+const (
+	DefaultMaxDirections  = 256
+	DefaultMaxFrames      = 1 << 16
+	DefaultMaxFrameWidth  = 1 << 14
+	DefaultMaxFrameHeight = 1 << 14
+	DefaultMaxTotalPixels = 1 << 24
+	DefaultMaxTotalBytes  = 1 << 28
+)
+
+// DecodeMode determines how inconsistencies in a DC6 file are handled.
+type DecodeMode int
+
+const (
+	// ModeDefault records the inconsistencies described by WarningKind as
+	// warnings, it is the same as ModeLenient.
+	ModeDefault DecodeMode = iota
+
+	// ModeStrict rejects files with any of the inconsistencies described by
+	// WarningKind.
+	ModeStrict
+
+	// ModeLenient decodes files with any of the inconsistencies described by
+	// WarningKind, recording them as warnings.
+	ModeLenient
+)
+
+// rejects reports whether an inconsistency of the given kind is an error.
+func (m DecodeMode) rejects(WarningKind) bool {
+	return m == ModeStrict
+}
+
+// DecodeOptions control how a DC6 is decoded. The zero value decodes the
+// IndexData of every frame up front, using the default resource limits.
+//
+// The resource limits are checked against the values read from the file
+// before any memory is allocated for them, so that a crafted file can not
+// exhaust memory. A limit of zero uses the default, a negative limit disables
+// the check.
+type DecodeOptions struct {
+	// Lazy defers decoding the IndexData of each frame until the frame is
+	// first used, see Frame.Decode.
+	Lazy bool
+
+	// Mode determines how inconsistencies in the file are handled, the
+	// inconsistencies that do not cause an error are available from
+	// DC6.Warnings.
+	Mode DecodeMode
+
+	// Salvage decodes as much of a corrupt file as possible, instead of
+	// failing on the first frame that can not be decoded. Frames that can not
+	// be decoded completely hold whatever pixels could be recovered (or are an
+	// empty placeholder frame, if nothing could be recovered), their errors
+	// are available from Frame.Err and DC6.FrameErrors. The file header and
+	// frame pointer table must be intact.
+	Salvage bool
+
+	MaxDirections  int // number of directions
+	MaxFrames      int // number of frames, across all directions
+	MaxFrameWidth  int // width of a single frame
+	MaxFrameHeight int // height of a single frame
+	MaxTotalPixels int // sum of the pixel counts of all frames
+	MaxTotalBytes  int // sum of the frame data sizes of all frames, including padding
+}
+
+// FromBytes decodes the given DC6 file data. The data is copied, so the
+// caller may reuse it once FromBytes returns.
+func (o DecodeOptions) FromBytes(data []byte) (result *DC6, err error) {
+	// lazily decoded frames and the gaps of the file are read from the data
+	// after FromBytes returns
+	data = append([]byte(nil), data...)
+
+	if result, err = o.Open(bytes.NewReader(data), int64(len(data))); err != nil {
+		return nil, err
+	}
+
+	if err = o.decodeFrames(result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Decode reads a DC6 from the given reader. The reader is consumed
+// sequentially, so the file never needs to be held in memory in its entirety.
+// Frames are read in the order of their frame pointers, padding between frames
+// is kept for DC6.Encode. Data following the last frame is reported as
+// trailing data, but it is not read, so it is not written by DC6.Encode.
+func (o DecodeOptions) Decode(r io.Reader) (result *DC6, err error) {
+	result = &DC6{mode: o.Mode, salvaging: o.Salvage}
+	limits := o.limiter()
+
+	numDirections, framesPerDirection, err := result.decodeHeader(r, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = result.decodeBody(r, numDirections*framesPerDirection, limits); err != nil {
+		return nil, err
+	}
+
+	if err = result.checkFramePointers(); err != nil {
+		return nil, err
+	}
+
+	if err = o.decodeFrames(result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (o DecodeOptions) decodeFrames(d *DC6) error {
+	if o.Lazy {
+		return nil
+	}
+
+	for idx := range d.Directions {
+		if err := d.Directions[idx].decodeFrames(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// limiter keeps track of the resources used while decoding a DC6.
+type limiter struct {
+	opts   DecodeOptions
+	pixels uint64
+	bytes  uint64
+}
+
+func (o DecodeOptions) limiter() *limiter {
+	defaults := []struct {
+		limit *int
+		value int
+	}{
+		{&o.MaxDirections, DefaultMaxDirections},
+		{&o.MaxFrames, DefaultMaxFrames},
+		{&o.MaxFrameWidth, DefaultMaxFrameWidth},
+		{&o.MaxFrameHeight, DefaultMaxFrameHeight},
+		{&o.MaxTotalPixels, DefaultMaxTotalPixels},
+		{&o.MaxTotalBytes, DefaultMaxTotalBytes},
+	}
+
+	for idx := range defaults {
+		if *defaults[idx].limit == 0 {
+			*defaults[idx].limit = defaults[idx].value
+		}
+	}
+
+	return &limiter{opts: o}
+}
+
+func exceeds(value uint64, limit int) bool {
+	return limit >= 0 && value > uint64(limit)
+}
+
+// checkHeader checks the number of directions and frames of the file header.
+func (l *limiter) checkHeader(numDirections, framesPerDirection uint32) error {
+	if exceeds(uint64(numDirections), l.opts.MaxDirections) {
+		return fmt.Errorf("%w, %d directions, the limit is %d", ErrLimitExceeded, numDirections, l.opts.MaxDirections)
+	}
+
+	totalFrames := uint64(numDirections) * uint64(framesPerDirection)
+	if exceeds(totalFrames, l.opts.MaxFrames) {
+		return fmt.Errorf("%w, %d frames, the limit is %d", ErrLimitExceeded, totalFrames, l.opts.MaxFrames)
+	}
+
+	return nil
+}
+
+// checkFrame checks the dimensions of a frame, and adds its pixels to the
+// total number of pixels.
+func (l *limiter) checkFrame(width, height uint32) error {
+	if exceeds(uint64(width), l.opts.MaxFrameWidth) || exceeds(uint64(height), l.opts.MaxFrameHeight) {
+		const fmtErr = "%w, %dx%d pixels, the limit is %dx%d"
+		return fmt.Errorf(fmtErr, ErrOversizedFrame, width, height, l.opts.MaxFrameWidth, l.opts.MaxFrameHeight)
+	}
+
+	l.pixels += uint64(width) * uint64(height)
+	if exceeds(l.pixels, l.opts.MaxTotalPixels) {
+		return fmt.Errorf("%w, %d pixels in total, the limit is %d", ErrLimitExceeded, l.pixels, l.opts.MaxTotalPixels)
+	}
+
+	return nil
+}
+
+// consume adds the given number of bytes to the total number of bytes read.
+func (l *limiter) consume(n uint64) error {
+	l.bytes += n
+	if exceeds(l.bytes, l.opts.MaxTotalBytes) {
+		return fmt.Errorf("%w, %d bytes in total, the limit is %d", ErrLimitExceeded, l.bytes, l.opts.MaxTotalBytes)
+	}
+
+	return nil
+}
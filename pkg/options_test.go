@@ -0,0 +1,72 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"runtime"
+	"testing"
+)
+
+// oversizedFile returns a DC6 file with a single frame of the given size,
+// whose frame data is a single end of scanline byte.
+func oversizedFile(t *testing.T, width, height uint32) []byte {
+	t.Helper()
+
+	d := New(1, 1)
+	d.Directions[0].AddFrame(&Frame{Width: 1, Height: 1, IndexData: []byte{0}, Mask: []bool{false}})
+
+	data, err := d.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pointer := binary.LittleEndian.Uint32(data[headerSize:])
+	if length := binary.LittleEndian.Uint32(data[pointer+28:]); length != 1 {
+		t.Fatalf("expected a single byte of frame data, got %d", length)
+	}
+
+	binary.LittleEndian.PutUint32(data[pointer+4:], width)
+	binary.LittleEndian.PutUint32(data[pointer+8:], height)
+
+	return data
+}
+
+func TestLimitTotalPixels(t *testing.T) {
+	data := oversizedFile(t, DefaultMaxFrameWidth, DefaultMaxFrameHeight)
+
+	if _, err := FromBytes(data); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("FromBytes: expected ErrLimitExceeded, got %v", err)
+	}
+
+	if _, err := Decode(bytes.NewReader(data)); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Decode: expected ErrLimitExceeded, got %v", err)
+	}
+
+	if _, err := Open(bytes.NewReader(data), int64(len(data))); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Open: expected ErrLimitExceeded, got %v", err)
+	}
+}
+
+func TestUnterminatedFrameIsNotAllocated(t *testing.T) {
+	const size = 4096 // within the default limits
+
+	data := oversizedFile(t, size, size)
+
+	var before, after runtime.MemStats
+
+	runtime.ReadMemStats(&before)
+
+	// other modes decode the frame, with the missing pixels transparent
+	_, err := DecodeOptions{Mode: ModeStrict}.FromBytes(data)
+
+	runtime.ReadMemStats(&after)
+
+	if !errors.Is(err, ErrBadTerminator) {
+		t.Errorf("expected ErrBadTerminator, got %v", err)
+	}
+
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > size*size/16 {
+		t.Errorf("decoding allocated %d bytes", allocated)
+	}
+}
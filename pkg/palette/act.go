@@ -0,0 +1,53 @@
+package palette
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+)
+
+// actFooterSize is the size of the optional footer of an ACT file: the number
+// of colors and the index of the transparent color, big-endian.
+const actFooterSize = 4
+
+// noTransparentIndex is the transparent index of an ACT file without one.
+const noTransparentIndex = 0xffff
+
+// DecodeACT reads an Adobe Color Table: 256 colors in RGB order, optionally
+// followed by the number of colors and the index of the transparent color.
+func DecodeACT(r io.Reader) (color.Palette, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) != colorsSize && len(data) != colorsSize+actFooterSize {
+		return nil, fmt.Errorf("expected %d or %d bytes, got %d", colorsSize, colorsSize+actFooterSize, len(data))
+	}
+
+	count, transparent := numColors, noTransparentIndex
+
+	if len(data) > colorsSize {
+		count = int(binary.BigEndian.Uint16(data[colorsSize:]))
+		transparent = int(binary.BigEndian.Uint16(data[colorsSize+2:]))
+
+		if count == 0 || count > numColors {
+			count = numColors
+		}
+	}
+
+	p := make(color.Palette, count)
+
+	for idx := range p {
+		c := data[idx*bytesPerColor : (idx+1)*bytesPerColor]
+		p[idx] = color.RGBA{R: c[0], G: c[1], B: c[2], A: math.MaxUint8}
+	}
+
+	if transparent < count {
+		p[transparent] = color.RGBA{}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,34 @@
+package palette
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DecodeColorMaps reads a Diablo II color map file, such as the invgreybrown
+// .dat files used to recolor items. Each color map is a Transform of 256
+// bytes, the file holds one color map per variant.
+func DecodeColorMaps(r io.Reader) ([]Transform, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not read color maps, %w", err)
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("could not decode color maps, no data")
+	}
+
+	if len(data)%numColors != 0 {
+		const fmtErr = "could not decode color maps, %d bytes is not a multiple of %d"
+		return nil, fmt.Errorf(fmtErr, len(data), numColors)
+	}
+
+	maps := make([]Transform, len(data)/numColors)
+
+	for idx := range maps {
+		copy(maps[idx][:], data[idx*numColors:])
+	}
+
+	return maps, nil
+}
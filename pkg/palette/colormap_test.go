@@ -0,0 +1,36 @@
+package palette
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeColorMaps(t *testing.T) {
+	data := make([]byte, 3*numColors)
+	for variant := 0; variant < 3; variant++ {
+		for idx := 0; idx < numColors; idx++ {
+			data[variant*numColors+idx] = byte(idx + variant)
+		}
+	}
+
+	maps, err := DecodeColorMaps(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(maps) != 3 {
+		t.Fatalf("expected 3 color maps, got %d", len(maps))
+	}
+
+	for variant := range maps {
+		if got := maps[variant][10]; got != byte(10+variant) {
+			t.Errorf("variant %d: expected %d, got %d", variant, 10+variant, got)
+		}
+	}
+
+	for _, size := range []int{0, 100, numColors + 1} {
+		if _, err := DecodeColorMaps(bytes.NewReader(make([]byte, size))); err == nil {
+			t.Errorf("expected an error for %d bytes", size)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package palette
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+)
+
+// DecodeDAT reads a Diablo II .dat palette: 256 colors in BGR order. Palette
+// index 0 is transparent, as it is in the game.
+func DecodeDAT(r io.Reader) (color.Palette, error) {
+	data := make([]byte, colorsSize)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("expected %d bytes, %w", colorsSize, err)
+	}
+
+	p := make(color.Palette, numColors)
+
+	for idx := range p {
+		b, g, r := data[idx*bytesPerColor], data[idx*bytesPerColor+1], data[idx*bytesPerColor+2]
+		p[idx] = color.RGBA{R: r, G: g, B: b, A: math.MaxUint8}
+	}
+
+	p[0] = color.RGBA{}
+
+	return p, nil
+}
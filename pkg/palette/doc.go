@@ -0,0 +1,5 @@
+// Package palette decodes color palettes in the formats used by Diablo II and
+// common image editors: Diablo II .dat palettes, JASC-PAL, Adobe ACT, RIFF PAL
+// and GIMP palettes. A Registry maps names to palettes, and can be populated
+// from the game's palette directory.
+package palette
@@ -0,0 +1,18 @@
+package palette
+
+import (
+	"image/color"
+	"io"
+
+	gpl "github.com/gravestench/gpl/pkg"
+)
+
+// DecodeGPL reads a GIMP palette.
+func DecodeGPL(r io.Reader) (color.Palette, error) {
+	p, err := gpl.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return color.Palette(p), nil
+}
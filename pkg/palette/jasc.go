@@ -0,0 +1,70 @@
+package palette
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DecodeJASC reads a JASC-PAL palette, as written by Paint Shop Pro.
+func DecodeJASC(r io.Reader) (color.Palette, error) {
+	scanner := bufio.NewScanner(r)
+
+	var lines []string
+
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	const headerLines = 3 // magic, version, number of colors
+
+	if len(lines) < headerLines || lines[0] != "JASC-PAL" {
+		return nil, errors.New("missing JASC-PAL header")
+	}
+
+	count, err := strconv.Atoi(lines[2])
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("invalid number of colors %q", lines[2])
+	}
+
+	if len(lines)-headerLines < count {
+		return nil, fmt.Errorf("expected %d colors, got %d", count, len(lines)-headerLines)
+	}
+
+	p := make(color.Palette, count)
+
+	for idx := range p {
+		line := lines[headerLines+idx]
+		fields := strings.Fields(line)
+
+		if len(fields) < bytesPerColor {
+			return nil, fmt.Errorf("invalid color %q", line)
+		}
+
+		var rgb [bytesPerColor]uint8
+
+		for component := range rgb {
+			v, err := strconv.ParseUint(fields[component], 10, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid color %q, %w", line, err)
+			}
+
+			rgb[component] = uint8(v)
+		}
+
+		p[idx] = color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: math.MaxUint8}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,143 @@
+package palette
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Format identifies a palette file format.
+type Format int
+
+const (
+	// FormatUnknown is returned by Detect for data that is not a palette.
+	FormatUnknown Format = iota
+
+	// FormatDAT is the Diablo II palette format: 256 colors of 3 bytes each,
+	// in BGR order. Palette index 0 is transparent.
+	FormatDAT
+
+	// FormatJASC is the text format of Paint Shop Pro, starting with JASC-PAL.
+	FormatJASC
+
+	// FormatACT is the Adobe Color Table format: 256 colors of 3 bytes each in
+	// RGB order, optionally followed by the number of colors and the index of
+	// the transparent color.
+	FormatACT
+
+	// FormatRIFF is the Microsoft RIFF palette format.
+	FormatRIFF
+
+	// FormatGPL is the text format of GIMP, starting with GIMP Palette.
+	FormatGPL
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatDAT:
+		return "dat"
+	case FormatJASC:
+		return "jasc-pal"
+	case FormatACT:
+		return "act"
+	case FormatRIFF:
+		return "riff"
+	case FormatGPL:
+		return "gpl"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	numColors     = 256
+	bytesPerColor = 3
+	colorsSize    = numColors * bytesPerColor
+)
+
+// ErrUnknownFormat is returned when the format of a palette can not be
+// detected.
+var ErrUnknownFormat = errors.New("unknown palette format")
+
+// Detect returns the format of the given palette data. The .dat and .act
+// formats can not be told apart by their content alone, the extension of the
+// file name (which may be empty) is used to tell them apart, .dat is assumed
+// otherwise.
+func Detect(data []byte, name string) Format {
+	switch {
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "PAL ":
+		return FormatRIFF
+	case bytes.HasPrefix(data, []byte("JASC-PAL")):
+		return FormatJASC
+	case bytes.HasPrefix(data, []byte("GIMP Palette")):
+		return FormatGPL
+	case len(data) == colorsSize+actFooterSize:
+		return FormatACT
+	case len(data) == colorsSize && strings.EqualFold(filepath.Ext(name), ".act"):
+		return FormatACT
+	case len(data) == colorsSize:
+		return FormatDAT
+	default:
+		return FormatUnknown
+	}
+}
+
+// Decode reads a palette from the given reader, detecting its format.
+func Decode(r io.Reader) (color.Palette, error) {
+	return decode(r, "")
+}
+
+// ReadFile reads the palette file at the given path, detecting its format.
+func ReadFile(path string) (color.Palette, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read palette, %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	return decode(f, path)
+}
+
+func decode(r io.Reader, name string) (color.Palette, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not read palette, %w", err)
+	}
+
+	format := Detect(data, name)
+	if format == FormatUnknown {
+		return nil, fmt.Errorf("could not decode palette, %w", ErrUnknownFormat)
+	}
+
+	return DecodeFormat(bytes.NewReader(data), format)
+}
+
+// DecodeFormat reads a palette of the given format from the given reader.
+func DecodeFormat(r io.Reader, format Format) (p color.Palette, err error) {
+	switch format {
+	case FormatDAT:
+		p, err = DecodeDAT(r)
+	case FormatJASC:
+		p, err = DecodeJASC(r)
+	case FormatACT:
+		p, err = DecodeACT(r)
+	case FormatRIFF:
+		p, err = DecodeRIFF(r)
+	case FormatGPL:
+		p, err = DecodeGPL(r)
+	default:
+		err = ErrUnknownFormat
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not decode %s palette, %w", format, err)
+	}
+
+	return p, nil
+}
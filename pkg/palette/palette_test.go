@@ -0,0 +1,204 @@
+package palette
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"testing"
+)
+
+// testColors returns 256 colors of 3 bytes each, color i is (i, i+1, i+2).
+func testColors() []byte {
+	data := make([]byte, colorsSize)
+
+	for idx := 0; idx < numColors; idx++ {
+		data[idx*bytesPerColor] = byte(idx)
+		data[idx*bytesPerColor+1] = byte(idx + 1)
+		data[idx*bytesPerColor+2] = byte(idx + 2)
+	}
+
+	return data
+}
+
+func opaque(r, g, b uint8) color.RGBA {
+	return color.RGBA{R: r, G: g, B: b, A: 0xff}
+}
+
+func TestDecodeDAT(t *testing.T) {
+	p, err := DecodeDAT(bytes.NewReader(testColors()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p) != numColors {
+		t.Fatalf("expected %d colors, got %d", numColors, len(p))
+	}
+
+	if p[0] != (color.RGBA{}) {
+		t.Errorf("expected index 0 to be transparent, got %v", p[0])
+	}
+
+	// colors are stored in BGR order
+	if want := opaque(12, 11, 10); p[10] != want {
+		t.Errorf("expected %v, got %v", want, p[10])
+	}
+
+	if _, err := DecodeDAT(bytes.NewReader(testColors()[:100])); err == nil {
+		t.Error("expected an error for a truncated palette")
+	}
+}
+
+func TestDecodeACT(t *testing.T) {
+	p, err := DecodeACT(bytes.NewReader(testColors()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// without a footer, there are 256 colors in RGB order and none is
+	// transparent
+	if len(p) != numColors || p[0] != opaque(0, 1, 2) || p[10] != opaque(10, 11, 12) {
+		t.Errorf("unexpected palette without footer, %d colors, %v, %v", len(p), p[0], p[10])
+	}
+
+	footer := make([]byte, actFooterSize)
+	binary.BigEndian.PutUint16(footer, 16)
+	binary.BigEndian.PutUint16(footer[2:], 3)
+
+	p, err = DecodeACT(bytes.NewReader(append(testColors(), footer...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p) != 16 {
+		t.Fatalf("expected the 16 colors of the footer, got %d", len(p))
+	}
+
+	if p[3] != (color.RGBA{}) {
+		t.Errorf("expected index 3 to be transparent, got %v", p[3])
+	}
+
+	if p[0] != opaque(0, 1, 2) {
+		t.Errorf("expected index 0 to be opaque, got %v", p[0])
+	}
+
+	if _, err := DecodeACT(bytes.NewReader(testColors()[:100])); err == nil {
+		t.Error("expected an error for a truncated palette")
+	}
+}
+
+// riffChunk returns a RIFF chunk with the given id and data, padded to an
+// even size.
+func riffChunk(id string, data []byte) []byte {
+	chunk := append([]byte(id), make([]byte, 4)...)
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(len(data)))
+	chunk = append(chunk, data...)
+
+	if len(data)%2 != 0 {
+		chunk = append(chunk, 0)
+	}
+
+	return chunk
+}
+
+func riffFile(chunks ...[]byte) []byte {
+	body := []byte("PAL ")
+	for _, chunk := range chunks {
+		body = append(body, chunk...)
+	}
+
+	data := append([]byte("RIFF"), make([]byte, 4)...)
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(body)))
+
+	return append(data, body...)
+}
+
+func TestDecodeRIFF(t *testing.T) {
+	colors := []byte{0x00, 0x03, 2, 0} // version 0x300, 2 colors
+	colors = append(colors, 10, 20, 30, 0, 40, 50, 60, 0)
+
+	// the data chunk follows a chunk of odd size, which is padded
+	data := riffFile(riffChunk("INFO", []byte{1, 2, 3}), riffChunk("data", colors))
+
+	if got := Detect(data, ""); got != FormatRIFF {
+		t.Errorf("expected %v, got %v", FormatRIFF, got)
+	}
+
+	p, err := DecodeRIFF(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p) != 2 || p[0] != opaque(10, 20, 30) || p[1] != opaque(40, 50, 60) {
+		t.Errorf("unexpected palette %v", p)
+	}
+
+	if _, err := DecodeRIFF(bytes.NewReader(riffFile(riffChunk("INFO", nil)))); err == nil {
+		t.Error("expected an error for a file without data chunk")
+	}
+
+	if _, err := DecodeRIFF(bytes.NewReader(data[:len(data)-4])); err == nil {
+		t.Error("expected an error for a truncated data chunk")
+	}
+}
+
+func TestDecodeJASC(t *testing.T) {
+	data := []byte("JASC-PAL\r\n0100\r\n3\r\n0 0 0\r\n255 128 1\r\n10 20 30\r\n")
+
+	if got := Detect(data, "palette.pal"); got != FormatJASC {
+		t.Errorf("expected %v, got %v", FormatJASC, got)
+	}
+
+	p, err := Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := color.Palette{opaque(0, 0, 0), opaque(255, 128, 1), opaque(10, 20, 30)}
+
+	if len(p) != len(want) {
+		t.Fatalf("expected %d colors, got %d", len(want), len(p))
+	}
+
+	for idx := range want {
+		if p[idx] != want[idx] {
+			t.Errorf("color %d: expected %v, got %v", idx, want[idx], p[idx])
+		}
+	}
+
+	if _, err := DecodeJASC(bytes.NewReader([]byte("JASC-PAL\r\n0100\r\n3\r\n0 0 0\r\n"))); err == nil {
+		t.Error("expected an error for missing colors")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	colors := testColors()
+
+	tests := []struct {
+		name string
+		data []byte
+		want Format
+	}{
+		{"pal.dat", colors, FormatDAT},
+		{"", colors, FormatDAT},
+		{"palette.act", colors, FormatACT},
+		{"PALETTE.ACT", colors, FormatACT},
+		{"palette.dat", append(colors, 0, 0, 0, 0), FormatACT},
+		{"palette.gpl", []byte("GIMP Palette\nName: test\n"), FormatGPL},
+		{"palette.dat", colors[:100], FormatUnknown},
+	}
+
+	for _, test := range tests {
+		if got := Detect(test.data, test.name); got != test.want {
+			t.Errorf("%q of %d bytes: expected %v, got %v", test.name, len(test.data), test.want, got)
+		}
+	}
+
+	// the extension decides whether index 0 is transparent
+	if p, err := decode(bytes.NewReader(colors), "palette.act"); err != nil || p[0] != opaque(0, 1, 2) {
+		t.Errorf("expected an opaque ACT color, got %v, %v", p, err)
+	}
+
+	if p, err := decode(bytes.NewReader(colors), "palette.dat"); err != nil || p[0] != (color.RGBA{}) {
+		t.Errorf("expected a transparent DAT color, got %v, %v", p, err)
+	}
+}
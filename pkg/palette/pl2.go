@@ -0,0 +1,145 @@
+package palette
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+)
+
+// Transform maps palette indices to palette indices, it is applied to the
+// pixels of a frame before their colors are looked up in the palette.
+type Transform [numColors]uint8
+
+// BlendTable maps a foreground and a background palette index to the palette
+// index of the blended color, indexed as table[foreground][background].
+type BlendTable [numColors]Transform
+
+const (
+	numLightLevels     = 32
+	numInvColors       = 16
+	numAlphaBlends     = 3
+	numHueVariations   = 111
+	numUnknownVariants = 14
+	numTextColors      = 13
+)
+
+// pl2Data is the layout of a .pl2 file.
+type pl2Data struct {
+	BasePalette     [numColors][4]uint8 // red, green, blue, unused
+	LightLevels     [numLightLevels]Transform
+	InvColors       [numInvColors]Transform
+	Selected        Transform
+	AlphaBlends     [numAlphaBlends]BlendTable
+	Additive        BlendTable
+	Multiplicative  BlendTable
+	HueVariations   [numHueVariations]Transform
+	RedTones        Transform
+	GreenTones      Transform
+	BlueTones       Transform
+	UnknownVariants [numUnknownVariants]Transform
+	MaxComponent    BlendTable
+	Darkened        Transform
+	TextColors      [numTextColors][3]uint8 // red, green, blue
+	TextColorShifts [numTextColors]Transform
+}
+
+// PL2 holds the palette transforms that Diablo II pairs with a .dat palette.
+type PL2 struct {
+	// BasePalette is the palette the transforms apply to, palette index 0 is
+	// transparent.
+	BasePalette color.Palette
+
+	// LightLevels shift the palette to each of the game's 32 light levels.
+	LightLevels [numLightLevels]Transform
+
+	// InvColors are the inventory color variations.
+	InvColors [numInvColors]Transform
+
+	// Selected highlights a unit under the mouse cursor.
+	Selected Transform
+
+	// AlphaBlends blend a foreground over a background, in the order of the
+	// game's 25%, 50% and 75% transparency levels.
+	AlphaBlends [numAlphaBlends]BlendTable
+
+	// Additive and Multiplicative blend a foreground with a background.
+	Additive       BlendTable
+	Multiplicative BlendTable
+
+	// HueVariations shift the hue of the palette.
+	HueVariations [numHueVariations]Transform
+
+	// RedTones, GreenTones and BlueTones tint the palette.
+	RedTones   Transform
+	GreenTones Transform
+	BlueTones  Transform
+
+	UnknownVariants [numUnknownVariants]Transform
+
+	// MaxComponent blends a foreground with a background by taking the
+	// maximum of each color component.
+	MaxComponent BlendTable
+
+	// Darkened darkens the palette.
+	Darkened Transform
+
+	// TextColors are the colors of the game's text, and TextColorShifts the
+	// transforms that recolor text to them.
+	TextColors      [numTextColors]color.RGBA
+	TextColorShifts [numTextColors]Transform
+}
+
+// DecodePL2 reads a Diablo II .pl2 palette transform file.
+func DecodePL2(r io.Reader) (*PL2, error) {
+	data := &pl2Data{}
+
+	if err := binary.Read(r, binary.LittleEndian, data); err != nil {
+		return nil, fmt.Errorf("could not decode pl2, expected %d bytes, %w", binary.Size(data), err)
+	}
+
+	pl2 := &PL2{
+		BasePalette:     make(color.Palette, numColors),
+		LightLevels:     data.LightLevels,
+		InvColors:       data.InvColors,
+		Selected:        data.Selected,
+		AlphaBlends:     data.AlphaBlends,
+		Additive:        data.Additive,
+		Multiplicative:  data.Multiplicative,
+		HueVariations:   data.HueVariations,
+		RedTones:        data.RedTones,
+		GreenTones:      data.GreenTones,
+		BlueTones:       data.BlueTones,
+		UnknownVariants: data.UnknownVariants,
+		MaxComponent:    data.MaxComponent,
+		Darkened:        data.Darkened,
+		TextColorShifts: data.TextColorShifts,
+	}
+
+	for idx, c := range data.BasePalette {
+		pl2.BasePalette[idx] = color.RGBA{R: c[0], G: c[1], B: c[2], A: math.MaxUint8}
+	}
+
+	pl2.BasePalette[0] = color.RGBA{}
+
+	for idx, c := range data.TextColors {
+		pl2.TextColors[idx] = color.RGBA{R: c[0], G: c[1], B: c[2], A: math.MaxUint8}
+	}
+
+	return pl2, nil
+}
+
+// LightLevel returns the transform of the given light level, which is clamped
+// to the range of light levels.
+func (p *PL2) LightLevel(level int) *Transform {
+	if level < 0 {
+		level = 0
+	}
+
+	if level >= numLightLevels {
+		level = numLightLevels - 1
+	}
+
+	return &p.LightLevels[level]
+}
@@ -0,0 +1,66 @@
+package palette
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"testing"
+)
+
+func TestPL2Size(t *testing.T) {
+	// the size of the game's .pl2 files
+	if size := binary.Size(pl2Data{}); size != 443175 {
+		t.Errorf("expected pl2 data of 443175 bytes, got %d", size)
+	}
+}
+
+func TestDecodePL2(t *testing.T) {
+	data := &pl2Data{}
+	data.BasePalette[0] = [4]uint8{1, 2, 3, 0}
+	data.BasePalette[5] = [4]uint8{10, 20, 30, 0}
+	data.LightLevels[31][5] = 7
+	data.AlphaBlends[1][5][9] = 11
+	data.TextColors[12] = [3]uint8{40, 50, 60}
+
+	buf := &bytes.Buffer{}
+	if err := binary.Write(buf, binary.LittleEndian, data); err != nil {
+		t.Fatal(err)
+	}
+
+	pl2, err := DecodePL2(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pl2.BasePalette[0] != (color.RGBA{}) {
+		t.Errorf("expected index 0 to be transparent, got %v", pl2.BasePalette[0])
+	}
+
+	if want := (color.RGBA{R: 10, G: 20, B: 30, A: 0xff}); pl2.BasePalette[5] != want {
+		t.Errorf("expected %v, got %v", want, pl2.BasePalette[5])
+	}
+
+	if pl2.LightLevels[31][5] != 7 || pl2.AlphaBlends[1][5][9] != 11 {
+		t.Error("transforms are not decoded")
+	}
+
+	if want := (color.RGBA{R: 40, G: 50, B: 60, A: 0xff}); pl2.TextColors[12] != want {
+		t.Errorf("expected text color %v, got %v", want, pl2.TextColors[12])
+	}
+
+	if _, err := DecodePL2(bytes.NewReader(buf.Bytes()[:1000])); err == nil {
+		t.Error("expected an error for a truncated file")
+	}
+}
+
+func TestLightLevel(t *testing.T) {
+	pl2 := &PL2{}
+
+	tests := map[int]int{-5: 0, 0: 0, 12: 12, numLightLevels - 1: numLightLevels - 1, 100: numLightLevels - 1}
+
+	for level, want := range tests {
+		if got := pl2.LightLevel(level); got != &pl2.LightLevels[want] {
+			t.Errorf("light level %d: expected level %d", level, want)
+		}
+	}
+}
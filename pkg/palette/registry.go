@@ -0,0 +1,212 @@
+package palette
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownPalette is returned when no palette is registered under a name.
+var ErrUnknownPalette = errors.New("unknown palette")
+
+// registryExtensions are the extensions of the files LoadFS reads.
+var registryExtensions = map[string]bool{
+	".dat": true,
+	".act": true,
+	".pal": true,
+	".gpl": true,
+}
+
+// Registry maps names, like "act1", "units" or "static", to palettes. Names
+// are not case-sensitive. A Registry can be used by multiple goroutines at
+// the same time.
+type Registry struct {
+	mu          sync.RWMutex
+	palettes    map[string]color.Palette
+	defaultName string
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{palettes: make(map[string]color.Palette)}
+}
+
+// Register adds a palette under the given name, replacing any palette that
+// was registered under it. The palette must not be modified after it is
+// registered.
+func (r *Registry) Register(name string, p color.Palette) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.palettes[strings.ToLower(name)] = p
+}
+
+// Lookup returns the palette registered under the given name.
+func (r *Registry) Lookup(name string) (color.Palette, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, found := r.palettes[strings.ToLower(name)]
+	if !found {
+		return nil, fmt.Errorf("%w %q", ErrUnknownPalette, name)
+	}
+
+	return p, nil
+}
+
+// Names returns the names of the registered palettes, in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.palettes))
+	for name := range r.palettes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// SetDefault selects the registered palette that Default returns, an empty
+// name clears the default palette.
+func (r *Registry) SetDefault(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name = strings.ToLower(name)
+
+	if _, found := r.palettes[name]; name != "" && !found {
+		return fmt.Errorf("could not set default palette, %w %q", ErrUnknownPalette, name)
+	}
+
+	r.defaultName = name
+
+	return nil
+}
+
+// Default returns the palette selected with SetDefault, or nil if no default
+// palette is selected.
+func (r *Registry) Default() color.Palette {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.defaultName == "" {
+		return nil
+	}
+
+	return r.palettes[r.defaultName]
+}
+
+// Resolve returns the palette registered under the given name, or else reads
+// the palette file at the given path.
+func (r *Registry) Resolve(nameOrPath string) (color.Palette, error) {
+	if p, err := r.Lookup(nameOrPath); err == nil {
+		return p, nil
+	}
+
+	p, err := ReadFile(nameOrPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w %q, and there is no file of that name", ErrUnknownPalette, nameOrPath)
+	}
+
+	return p, err
+}
+
+// LoadDir registers the palettes in the given directory, see LoadFS.
+func (r *Registry) LoadDir(dir string) error {
+	return r.LoadFS(os.DirFS(dir))
+}
+
+// LoadFS registers the palette files (.dat, .act, .pal and .gpl) in the given
+// file system and its subdirectories. A palette is named after its file
+// without the extension, except for files named pal.*, which are named after
+// their directory. This matches the layout of the game's data/global/palette
+// directory, in which ACT1/pal.dat is registered as "act1". Files of an
+// unknown format are skipped. If a name is used by more than one palette, no
+// palettes are registered.
+func (r *Registry) LoadFS(fsys fs.FS) error {
+	palettes := make(map[string]color.Palette)
+	paths := make(map[string]string)
+
+	err := fs.WalkDir(fsys, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		ext := strings.ToLower(path.Ext(filePath))
+		if entry.IsDir() || !registryExtensions[ext] {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return err
+		}
+
+		p, err := decode(bytes.NewReader(data), filePath)
+		if errors.Is(err, ErrUnknownFormat) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
+
+		name := registryName(filePath)
+
+		if other, found := paths[name]; found {
+			return fmt.Errorf("palette %q is defined by %s and %s", name, other, filePath)
+		}
+
+		palettes[name], paths[name] = p, filePath
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not load palettes, %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, p := range palettes {
+		r.palettes[name] = p
+	}
+
+	return nil
+}
+
+// registryName returns the name LoadFS registers the palette file at the given
+// path under.
+func registryName(filePath string) string {
+	base := path.Base(filePath)
+	name := strings.ToLower(strings.TrimSuffix(base, path.Ext(base)))
+
+	if dir := path.Dir(filePath); name == "pal" && dir != "." {
+		name = strings.ToLower(path.Base(dir))
+	}
+
+	return name
+}
+
+// Debug returns a grayscale ramp in which the gray level of every color is
+// its palette index. It is not a game palette, it is meant for inspecting the
+// palette indices of frames when no game palette is available.
+func Debug() color.Palette {
+	p := make(color.Palette, numColors)
+
+	for idx := range p {
+		gray := uint8(idx)
+		p[idx] = color.RGBA{R: gray, G: gray, B: gray, A: math.MaxUint8}
+	}
+
+	return p
+}
@@ -0,0 +1,100 @@
+package palette
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadFS(t *testing.T) {
+	colors := testColors()
+
+	fsys := fstest.MapFS{
+		"ACT1/Pal.dat":  {Data: colors},
+		"Units.act":     {Data: colors},
+		"broken.pal":    {Data: []byte("not a palette")},
+		"readme.txt":    {Data: []byte("JASC-PAL")},
+		"fonts/pal.PL2": {Data: make([]byte, 100)},
+	}
+
+	r := NewRegistry()
+	if err := r.LoadFS(fsys); err != nil {
+		t.Fatal(err)
+	}
+
+	names := r.Names()
+	if len(names) != 2 || names[0] != "act1" || names[1] != "units" {
+		t.Fatalf("expected the palettes act1 and units, got %v", names)
+	}
+
+	act1, err := r.Lookup("Act1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// pal.dat is a .dat palette, its index 0 is transparent
+	if _, _, _, a := act1[0].RGBA(); a != 0 {
+		t.Errorf("expected index 0 of act1 to be transparent, got %v", act1[0])
+	}
+
+	units, err := r.Lookup("UNITS")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, a := units[0].RGBA(); a == 0 {
+		t.Error("expected index 0 of units to be opaque")
+	}
+
+	if _, err := r.Lookup("act2"); !errors.Is(err, ErrUnknownPalette) {
+		t.Errorf("expected ErrUnknownPalette, got %v", err)
+	}
+}
+
+func TestLoadFSDuplicateName(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a/static.dat": {Data: testColors()},
+		"b/static.act": {Data: testColors()},
+		"units.dat":    {Data: testColors()},
+	}
+
+	r := NewRegistry()
+	if err := r.LoadFS(fsys); err == nil {
+		t.Fatal("expected an error for a duplicate name")
+	}
+
+	if names := r.Names(); len(names) != 0 {
+		t.Errorf("expected no palettes to be registered, got %v", names)
+	}
+}
+
+func TestRegistryDefault(t *testing.T) {
+	r := NewRegistry()
+
+	if r.Default() != nil {
+		t.Error("expected no default palette")
+	}
+
+	p := Debug()
+	r.Register("Debug", p)
+
+	if err := r.SetDefault("DEBUG"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := r.Default(); len(got) != len(p) || got[10] != p[10] {
+		t.Error("expected the debug palette as default palette")
+	}
+
+	if err := r.SetDefault("missing"); !errors.Is(err, ErrUnknownPalette) {
+		t.Errorf("expected ErrUnknownPalette, got %v", err)
+	}
+
+	if r.Default() == nil {
+		t.Error("expected a failed SetDefault to keep the default palette")
+	}
+
+	if err := r.SetDefault(""); err != nil || r.Default() != nil {
+		t.Errorf("expected the default palette to be cleared, %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package palette
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+)
+
+const (
+	riffHeaderSize      = 12 // "RIFF", size, "PAL "
+	riffChunkHeaderSize = 8  // id, size
+	riffPaletteHeader   = 4  // version, number of colors
+	riffColorSize       = 4  // red, green, blue, flags
+)
+
+// DecodeRIFF reads a Microsoft RIFF palette, the colors of its data chunk.
+func DecodeRIFF(r io.Reader) (color.Palette, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < riffHeaderSize || string(data[:4]) != "RIFF" || string(data[8:12]) != "PAL " {
+		return nil, errors.New("missing RIFF PAL header")
+	}
+
+	for offset := riffHeaderSize; offset+riffChunkHeaderSize <= len(data); {
+		id := string(data[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(data[offset+4:]))
+		offset += riffChunkHeaderSize
+
+		if size < 0 || size > len(data)-offset {
+			return nil, fmt.Errorf("chunk %q of %d bytes exceeds the file", id, size)
+		}
+
+		if id == "data" {
+			return decodeRIFFData(data[offset : offset+size])
+		}
+
+		// chunks are padded to an even size
+		offset += size + size%2
+	}
+
+	return nil, errors.New("missing data chunk")
+}
+
+func decodeRIFFData(data []byte) (color.Palette, error) {
+	if len(data) < riffPaletteHeader {
+		return nil, errors.New("data chunk is too small")
+	}
+
+	count := int(binary.LittleEndian.Uint16(data[2:]))
+	data = data[riffPaletteHeader:]
+
+	if count*riffColorSize > len(data) {
+		return nil, fmt.Errorf("data chunk has room for %d of %d colors", len(data)/riffColorSize, count)
+	}
+
+	p := make(color.Palette, count)
+
+	for idx := range p {
+		c := data[idx*riffColorSize : (idx+1)*riffColorSize]
+		p[idx] = color.RGBA{R: c[0], G: c[1], B: c[2], A: math.MaxUint8}
+	}
+
+	return p, nil
+}
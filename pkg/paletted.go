@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+const numPaletteIndices = 256
+
+// ToPaletted returns the frame as an indexed image, with the same bounds as
+// the frame. The palette indices of the frame are preserved as-is, except for
+// transparent pixels which get palette index 0.
+//
+// The palette of the image is the palette of the DC6 with entry 0 replaced by
+// a transparent color, which is how the game treats palette index 0. It always
+// has 256 entries, entries missing from the DC6 palette are transparent.
+func (f *Frame) ToPaletted() *image.Paletted {
+	img := image.NewPaletted(f.Bounds(), transparentPalette(f.palette()))
+
+	indexData := f.indexData()
+
+	for idx := range img.Pix {
+		if f.opaque(idx, indexData) {
+			img.Pix[idx] = indexData[idx]
+		}
+	}
+
+	return img
+}
+
+// ToPaletted returns the given frame as an indexed image, see Frame.ToPaletted.
+func (d *DC6) ToPaletted(direction, frame int) (*image.Paletted, error) {
+	f, err := d.frameAt(direction, frame)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = f.Decode(); err != nil {
+		return nil, err
+	}
+
+	return f.ToPaletted(), nil
+}
+
+// frameAt returns the given frame, or an error if it does not exist.
+func (d *DC6) frameAt(direction, frame int) (*Frame, error) {
+	if direction < 0 || direction >= len(d.Directions) || d.Directions[direction] == nil {
+		return nil, fmt.Errorf("direction %d out of range, the dc6 has %d directions", direction, len(d.Directions))
+	}
+
+	frames := d.Directions[direction].Frames
+	if frame < 0 || frame >= len(frames) || frames[frame] == nil {
+		return nil, fmt.Errorf("frame %d out of range, the direction has %d frames", frame, len(frames))
+	}
+
+	return frames[frame], nil
+}
+
+// transparentPalette returns a copy of the given palette with 256 entries,
+// where entry 0 and any missing entries are transparent.
+func transparentPalette(p color.Palette) color.Palette {
+	palette := make(color.Palette, numPaletteIndices)
+
+	for idx := range palette {
+		palette[idx] = color.Transparent
+		if idx > 0 && idx < len(p) {
+			palette[idx] = p[idx]
+		}
+	}
+
+	return palette
+}
@@ -0,0 +1,94 @@
+package pkg
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/gravestench/dc6/pkg/palette"
+)
+
+var _ image.PalettedImage = &RemappedFrame{}
+
+// RemappedFrame is a view of a frame with the palette indices of its opaque
+// pixels mapped through a color map, such as the item color variations of
+// palette.DecodeColorMaps. The frame itself is not modified.
+type RemappedFrame struct {
+	frame    *Frame
+	colorMap *palette.Transform
+}
+
+// Remapped returns a view of the frame with its palette indices mapped through
+// the given color map.
+func (f *Frame) Remapped(colorMap *palette.Transform) *RemappedFrame {
+	return &RemappedFrame{frame: f, colorMap: colorMap}
+}
+
+// Frame returns the frame that is remapped.
+func (r *RemappedFrame) Frame() *Frame {
+	return r.frame
+}
+
+// ColorIndexAt returns the remapped palette index of the pixel at the given
+// location, in the coordinate space of Bounds. Transparent pixels, and
+// locations outside of the frame, yield 0.
+func (r *RemappedFrame) ColorIndexAt(x, y int) uint8 {
+	if r.frame.IsTransparent(x, y) {
+		return 0
+	}
+
+	return r.colorMap[r.frame.ColorIndexAt(x, y)]
+}
+
+// ColorModel returns the color model of the frame, see Frame.ColorModel.
+func (r *RemappedFrame) ColorModel() color.Model {
+	return r.frame.ColorModel()
+}
+
+// Bounds returns the bounds of the frame.
+func (r *RemappedFrame) Bounds() image.Rectangle {
+	return r.frame.Bounds()
+}
+
+// At returns the remapped color of the pixel at the given location, in the
+// coordinate space of Bounds. Transparent pixels, and locations outside of the
+// frame, yield color.Transparent.
+func (r *RemappedFrame) At(x, y int) color.Color {
+	if r.frame.IsTransparent(x, y) {
+		return color.Transparent
+	}
+
+	palette := r.frame.palette()
+
+	cidx := int(r.ColorIndexAt(x, y))
+	if cidx >= len(palette) {
+		return color.Transparent
+	}
+
+	return palette[cidx]
+}
+
+// ToImageRGBA returns the remapped frame as an RGBA image, see
+// Frame.ToImageRGBA.
+func (r *RemappedFrame) ToImageRGBA() *image.RGBA {
+	return r.frame.ToImageRGBATransformed(r.colorMap)
+}
+
+// DrawInto draws the remapped frame into dst, see Frame.DrawInto.
+func (r *RemappedFrame) DrawInto(dst *image.RGBA) {
+	r.frame.DrawIntoTransformed(dst, r.colorMap)
+}
+
+// ToPaletted returns the remapped frame as an indexed image, see
+// Frame.ToPaletted.
+func (r *RemappedFrame) ToPaletted() *image.Paletted {
+	img := r.frame.ToPaletted()
+	indexData := r.frame.indexData()
+
+	for idx := range img.Pix {
+		if r.frame.opaque(idx, indexData) {
+			img.Pix[idx] = r.colorMap[img.Pix[idx]]
+		}
+	}
+
+	return img
+}
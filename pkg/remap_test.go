@@ -0,0 +1,50 @@
+package pkg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/gravestench/dc6/pkg/palette"
+)
+
+func TestRemappedFrame(t *testing.T) {
+	frame := twoPixelFrame()
+
+	// every palette index is mapped to 3, including that of the transparent pixel
+	colorMap := &palette.Transform{}
+	for idx := range colorMap {
+		colorMap[idx] = 3
+	}
+
+	remapped := frame.Remapped(colorMap)
+	opaque, transparent := frame.Bounds().Min, frame.Bounds().Min.Add(image.Pt(1, 0))
+	blue := color.RGBA{B: 3, A: 0xff}
+
+	if got := remapped.ColorIndexAt(opaque.X, opaque.Y); got != 3 {
+		t.Errorf("expected remapped index 3, got %d", got)
+	}
+
+	if got := remapped.ColorIndexAt(transparent.X, transparent.Y); got != 0 {
+		t.Errorf("expected index 0 for a transparent pixel, got %d", got)
+	}
+
+	if got := remapped.At(transparent.X, transparent.Y); got != color.Transparent {
+		t.Errorf("expected a transparent color, got %v", got)
+	}
+
+	paletted := remapped.ToPaletted()
+	if paletted.Pix[0] != 3 || paletted.Pix[1] != 0 {
+		t.Errorf("expected indices 3 and 0, got %v", paletted.Pix)
+	}
+
+	img := remapped.ToImageRGBA()
+	if img.RGBAAt(0, 0) != blue || img.RGBAAt(1, 0).A != 0 {
+		t.Errorf("expected %v and a transparent pixel, got %v and %v", blue, img.RGBAAt(0, 0), img.RGBAAt(1, 0))
+	}
+
+	// the frame itself is not modified
+	if frame.IndexData[0] != 1 || frame.IndexData[1] != 2 {
+		t.Errorf("the frame was modified, %v", frame.IndexData)
+	}
+}
@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// RetargetStats describes how well the pixels of a frame could be mapped to a
+// target palette, see DC6.Retarget. Errors are distances in the Oklab color
+// space, where 0 is an exact match and 1 is the distance between black and
+// white.
+type RetargetStats struct {
+	Direction int
+	Frame     int
+	Pixels    int     // the number of opaque pixels
+	Exact     int     // the number of opaque pixels that have an exact match
+	MeanError float64 // the mean error of the opaque pixels
+	MaxError  float64 // the largest error of the opaque pixels
+}
+
+// Retarget maps the opaque pixels of every frame from the source palette to
+// the perceptually nearest colors of the target palette, and sets the target
+// palette as the palette of the DC6. Transparent pixels stay transparent,
+// palette index 0 and fully transparent colors of the target palette are not
+// used for opaque pixels.
+//
+// Frames are decoded as needed. If a frame can not be decoded, or uses a
+// palette index that is not in the source palette, no frame is modified.
+func (d *DC6) Retarget(source, target color.Palette) ([]RetargetStats, error) {
+	q, err := ImportOptions{Palette: target}.quantizer()
+	if err != nil {
+		return nil, fmt.Errorf("could not retarget dc6, %w", err)
+	}
+
+	sourceColors := make([]oklab, len(source))
+	for idx, c := range source {
+		if c != nil {
+			sourceColors[idx] = colorToOklab(c)
+		}
+	}
+
+	// the target index and error of every source index, computed on first use
+	type mapping struct {
+		index uint8
+		err   float64
+		valid bool
+	}
+
+	var mappings [numPaletteIndices]mapping
+
+	var stats []RetargetStats
+
+	for dirIdx, direction := range d.Directions {
+		if direction == nil {
+			continue
+		}
+
+		for frameIdx, frame := range direction.Frames {
+			if frame == nil {
+				continue
+			}
+
+			if err := frame.Decode(); err != nil {
+				return nil, fmt.Errorf("could not retarget direction %d frame %d, %w", dirIdx, frameIdx, err)
+			}
+
+			frameStats := RetargetStats{Direction: dirIdx, Frame: frameIdx}
+
+			for idx, cidx := range frame.IndexData {
+				if !frame.opaque(idx, frame.IndexData) {
+					continue
+				}
+
+				if int(cidx) >= len(source) || source[cidx] == nil {
+					const fmtErr = "could not retarget direction %d frame %d, palette index %d is not in the source palette"
+					return nil, fmt.Errorf(fmtErr, dirIdx, frameIdx, cidx)
+				}
+
+				m := &mappings[cidx]
+				if !m.valid {
+					m.index = q.nearest(sourceColors[cidx])
+					m.err = math.Sqrt(sourceColors[cidx].distance(q.colors[m.index]))
+					m.valid = true
+				}
+
+				frameStats.Pixels++
+				frameStats.MeanError += m.err
+
+				if m.err == 0 {
+					frameStats.Exact++
+				}
+
+				if m.err > frameStats.MaxError {
+					frameStats.MaxError = m.err
+				}
+			}
+
+			if frameStats.Pixels > 0 {
+				frameStats.MeanError /= float64(frameStats.Pixels)
+			}
+
+			stats = append(stats, frameStats)
+		}
+	}
+
+	// all frames could be mapped, rewrite them
+	for _, direction := range d.Directions {
+		if direction == nil {
+			continue
+		}
+
+		for _, frame := range direction.Frames {
+			if frame == nil {
+				continue
+			}
+
+			for idx, cidx := range frame.IndexData {
+				if frame.opaque(idx, frame.IndexData) {
+					frame.IndexData[idx] = mappings[cidx].index
+				}
+			}
+		}
+	}
+
+	d.SetPalette(target)
+
+	return stats, nil
+}
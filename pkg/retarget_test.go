@@ -0,0 +1,79 @@
+package pkg
+
+import (
+	"bytes"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestRetarget(t *testing.T) {
+	source := color.Palette{color.RGBA{}, red, white, gray}
+	darkGray := color.RGBA{R: 0x70, G: 0x70, B: 0x70, A: 0xff}
+	target := color.Palette{black, white, red, darkGray}
+
+	d := New(1, 1)
+	d.SetPalette(source)
+	d.Directions[0].AddFrame(&Frame{
+		Width:     4,
+		Height:    1,
+		IndexData: []byte{1, 2, 3, 2},
+		Mask:      []bool{true, true, true, false},
+	})
+
+	stats, err := d.Retarget(source, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame := d.Directions[0].Frames[0]
+
+	// the transparent pixel keeps its index and stays transparent
+	if want := []byte{2, 1, 3, 2}; !bytes.Equal(frame.IndexData, want) {
+		t.Errorf("expected palette indices %v, got %v", want, frame.IndexData)
+	}
+
+	if frame.Mask[3] {
+		t.Error("expected the transparent pixel to stay transparent")
+	}
+
+	if len(stats) != 1 {
+		t.Fatalf("expected the stats of 1 frame, got %d", len(stats))
+	}
+
+	s := stats[0]
+
+	if s.Pixels != 3 || s.Exact != 2 {
+		t.Errorf("expected 2 of 3 exact pixels, got %d of %d", s.Exact, s.Pixels)
+	}
+
+	if s.MaxError <= 0 || math.Abs(s.MeanError-s.MaxError/3) > 1e-9 {
+		t.Errorf("expected a mean error of a third of the max error, got %v and %v", s.MeanError, s.MaxError)
+	}
+
+	if d.palette[3] != darkGray {
+		t.Error("expected the target palette to be set")
+	}
+}
+
+func TestRetargetMissingIndex(t *testing.T) {
+	source := color.Palette{color.RGBA{}, red, white}
+
+	d := New(1, 2)
+	d.SetPalette(source)
+	d.Directions[0].AddFrame(&Frame{Width: 2, Height: 1, IndexData: []byte{1, 2}, Mask: []bool{true, true}})
+	d.Directions[0].AddFrame(&Frame{Width: 1, Height: 1, IndexData: []byte{9}, Mask: []bool{true}})
+
+	if _, err := d.Retarget(source, color.Palette{black, white, red}); err == nil {
+		t.Fatal("expected an error for an index that is not in the source palette")
+	}
+
+	// no frame is modified
+	if got := d.Directions[0].Frames[0].IndexData; !bytes.Equal(got, []byte{1, 2}) {
+		t.Errorf("expected the first frame to be unchanged, got %v", got)
+	}
+
+	if len(d.palette) != len(source) {
+		t.Error("expected the palette to be unchanged")
+	}
+}
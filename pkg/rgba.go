@@ -0,0 +1,134 @@
+package pkg
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/gravestench/dc6/pkg/palette"
+)
+
+// rgbaLUT maps each palette index to its color, as stored in the Pix of an
+// image.RGBA. Indices missing from the palette map to transparent.
+type rgbaLUT [numPaletteIndices]color.RGBA
+
+func newRGBALUT(p color.Palette) *rgbaLUT {
+	lut := &rgbaLUT{}
+
+	for idx := range lut {
+		if idx < len(p) && p[idx] != nil {
+			lut[idx] = color.RGBAModel.Convert(p[idx]).(color.RGBA)
+		}
+	}
+
+	return lut
+}
+
+// rgbaLUT returns the lookup table of the palette of the DC6 the frame
+// belongs to.
+func (f *Frame) rgbaLUT() *rgbaLUT {
+	if f.dc6 == nil {
+		return defaultRGBALUT()
+	}
+
+	return f.dc6.rgbaLUT()
+}
+
+// ToImageRGBA returns the frame as an RGBA image, with its origin at (0, 0)
+// rather than at the frame's offset. Transparent pixels have alpha 0.
+func (f *Frame) ToImageRGBA() *image.RGBA {
+	img := image.NewRGBA(image.Rectangle{
+		Max: f.Bounds().Size(),
+	})
+
+	f.drawInto(img, image.Point{}, nil)
+
+	return img
+}
+
+// ToImageRGBATransformed returns the frame as an RGBA image like ToImageRGBA,
+// with the palette indices of its pixels mapped through the given transform,
+// for example a light level of a palette.PL2.
+func (f *Frame) ToImageRGBATransformed(t *palette.Transform) *image.RGBA {
+	img := image.NewRGBA(image.Rectangle{
+		Max: f.Bounds().Size(),
+	})
+
+	f.drawInto(img, image.Point{}, t)
+
+	return img
+}
+
+// DrawInto draws the opaque pixels of the frame into dst, in the coordinate
+// space of Bounds, clipped to the bounds of dst. Transparent pixels leave dst
+// as it is, so a reused dst needs to be cleared first.
+func (f *Frame) DrawInto(dst *image.RGBA) {
+	f.drawInto(dst, f.Bounds().Min, nil)
+}
+
+// DrawIntoTransformed draws the frame into dst like DrawInto, with the palette
+// indices of its pixels mapped through the given transform.
+func (f *Frame) DrawIntoTransformed(dst *image.RGBA, t *palette.Transform) {
+	f.drawInto(dst, f.Bounds().Min, t)
+}
+
+// BlendOnto blends the opaque pixels of the frame onto dst, whose pixels are
+// palette indices of the same palette, in the coordinate space of Bounds and
+// clipped to the bounds of dst. Each blended pixel of dst becomes
+// table[frame pixel][dst pixel], for example with one of the AlphaBlends of a
+// palette.PL2.
+func (f *Frame) BlendOnto(dst *image.Paletted, table *palette.BlendTable) {
+	indexData := f.indexData()
+	origin := f.Bounds().Min
+	width := int(f.Width)
+	rect := f.Bounds().Intersect(dst.Rect)
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		row := (y - origin.Y) * width
+		pix := dst.PixOffset(rect.Min.X, y)
+
+		for x := rect.Min.X; x < rect.Max.X; x, pix = x+1, pix+1 {
+			idx := row + x - origin.X
+			if f.opaque(idx, indexData) {
+				dst.Pix[pix] = table[indexData[idx]][dst.Pix[pix]]
+			}
+		}
+	}
+}
+
+// drawInto draws the opaque pixels of the frame into dst, with the top-left
+// pixel of the frame at origin. Colors are looked up in the palette's LUT and
+// written to the Pix of dst directly, palette indices are mapped through the
+// transform first if it is not nil.
+func (f *Frame) drawInto(dst *image.RGBA, origin image.Point, t *palette.Transform) {
+	indexData := f.indexData()
+	lut := f.rgbaLUT()
+
+	width := int(f.Width)
+	rect := image.Rectangle{
+		Min: origin,
+		Max: origin.Add(image.Point{X: width, Y: int(f.Height)}),
+	}.Intersect(dst.Rect)
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		row := (y - origin.Y) * width
+		pix := dst.PixOffset(rect.Min.X, y)
+
+		for x := rect.Min.X; x < rect.Max.X; x, pix = x+1, pix+4 {
+			idx := row + x - origin.X
+			if !f.opaque(idx, indexData) {
+				continue
+			}
+
+			cidx := indexData[idx]
+			if t != nil {
+				cidx = t[cidx]
+			}
+
+			c := lut[cidx]
+			dst.Pix[pix+0] = c.R
+			dst.Pix[pix+1] = c.G
+			dst.Pix[pix+2] = c.B
+			dst.Pix[pix+3] = c.A
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package pkg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/gravestench/dc6/pkg/palette"
+)
+
+// twoPixelFrame returns a frame with an opaque pixel of palette index 1 and a
+// transparent pixel, offset by (10, 20).
+func twoPixelFrame() *Frame {
+	d := New(1, 1)
+	d.SetPalette(color.Palette{
+		color.RGBA{},
+		color.RGBA{R: 1, A: 0xff},
+		color.RGBA{G: 2, A: 0xff},
+		color.RGBA{B: 3, A: 0xff},
+	})
+
+	frame := &Frame{
+		Width:     2,
+		Height:    1,
+		OffsetX:   10,
+		OffsetY:   20,
+		IndexData: []byte{1, 2},
+		Mask:      []bool{true, false},
+	}
+
+	d.Directions[0].AddFrame(frame)
+
+	return frame
+}
+
+func TestToImageRGBATransformed(t *testing.T) {
+	frame := twoPixelFrame()
+
+	transform := &palette.Transform{}
+	for idx := range transform {
+		transform[idx] = uint8(idx)
+	}
+
+	transform[1] = 3
+
+	img := frame.ToImageRGBATransformed(transform)
+
+	if want := (color.RGBA{B: 3, A: 0xff}); img.RGBAAt(0, 0) != want {
+		t.Errorf("expected the transformed color %v, got %v", want, img.RGBAAt(0, 0))
+	}
+
+	if got := img.RGBAAt(1, 0); got.A != 0 {
+		t.Errorf("expected a transparent pixel, got %v", got)
+	}
+}
+
+func TestBlendOnto(t *testing.T) {
+	frame := twoPixelFrame()
+
+	table := &palette.BlendTable{}
+	table[1][7] = 42 // frame pixel 1 over background 7
+	table[7][1] = 99
+
+	dst := image.NewPaletted(image.Rect(9, 20, 13, 21), nil)
+	for idx := range dst.Pix {
+		dst.Pix[idx] = 7
+	}
+
+	frame.BlendOnto(dst, table)
+
+	want := []uint8{7, 42, 7, 7}
+	for idx := range want {
+		if dst.Pix[idx] != want[idx] {
+			t.Errorf("pixel %d: expected %d, got %d", idx, want[idx], dst.Pix[idx])
+		}
+	}
+}
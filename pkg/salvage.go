@@ -0,0 +1,63 @@
+package pkg
+
+// salvage handles a frame that could not be decoded. Unless the DC6 is being
+// decoded in salvage mode (see DecodeOptions.Salvage), the error is returned
+// as-is. Otherwise the error is recorded on the frame and nil is returned, a
+// placeholder frame is used if the frame header could not be decoded.
+func (d *DC6) salvage(idx int, frame *Frame, err error) error {
+	if !d.salvaging {
+		return err
+	}
+
+	if frame == nil {
+		frame = &Frame{dc6: d}
+	}
+
+	frame.fail(err)
+	d.setFrame(idx, frame)
+
+	return nil
+}
+
+func (f *Frame) salvaging() bool {
+	return f.dc6 != nil && f.dc6.salvaging
+}
+
+// fail records the first error of a salvaged frame. The frame is marked dirty,
+// so that it is re-encoded from whatever could be recovered of its IndexData.
+func (f *Frame) fail(err error) {
+	if f.err == nil {
+		f.err = err
+	}
+
+	f.dirty = true
+}
+
+// Err returns the error of a frame that could not be decoded completely, when
+// decoding in salvage mode.
+func (f *Frame) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.err
+}
+
+// FrameErrors returns the errors of all frames that could not be decoded
+// completely, when decoding in salvage mode.
+func (d *DC6) FrameErrors() []error {
+	var errs []error
+
+	for dirIdx := range d.Directions {
+		for _, frame := range d.Directions[dirIdx].Frames {
+			if frame == nil {
+				continue
+			}
+
+			if err := frame.Err(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errs
+}
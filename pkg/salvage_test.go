@@ -0,0 +1,100 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// truncatedFile returns a DC6 file with three frames in one direction, which
+// is cut off the given number of bytes into the block of the last frame.
+func truncatedFile(t *testing.T, blockBytes int) (*DC6, []byte) {
+	t.Helper()
+
+	d := newTestDC6(1, 3)
+
+	data, err := d.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pointer := int(binary.LittleEndian.Uint32(data[headerSize+2*framePointerSize:]))
+
+	return d, data[:pointer+blockBytes]
+}
+
+func TestSalvageTruncatedFrameData(t *testing.T) {
+	want, data := truncatedFile(t, frameHeaderSize+300)
+
+	if _, err := FromBytes(data); !errors.Is(err, ErrTruncatedFrameData) {
+		t.Errorf("expected ErrTruncatedFrameData without salvaging, got %v", err)
+	}
+
+	for name, decode := range decoders(DecodeOptions{Salvage: true}) {
+		d, err := decode(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if errs := d.FrameErrors(); len(errs) != 1 || !errors.Is(errs[0], ErrTruncatedFrameData) {
+			t.Fatalf("%s: expected a truncated frame, got %v", name, errs)
+		}
+
+		// the intact frames are decoded, the truncated frame differs
+		for _, diff := range d.Diff(want) {
+			if diff.Frame != 2 || diff.Field != "Pixels" {
+				t.Errorf("%s: unexpected difference %v", name, diff)
+			}
+		}
+
+		// the scanlines that could be read are recovered, starting with the
+		// bottom scanline
+		got, original := d.Directions[0].Frames[2], want.Directions[0].Frames[2]
+		width, height := int(original.Width), int(original.Height)
+		bottom := (height - 1) * width
+
+		if !bytes.Equal(got.IndexData[bottom:], original.IndexData[bottom:]) {
+			t.Errorf("%s: the bottom scanline was not recovered", name)
+		}
+
+		// the salvaged frame is re-encoded from what was recovered
+		encoded, err := d.ToBytes()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if reencoded, err := FromBytes(encoded); err != nil || !reencoded.Equal(d) {
+			t.Errorf("%s: re-encoded salvaged DC6 differs, %v", name, err)
+		}
+	}
+}
+
+func TestSalvageTruncatedFrameHeader(t *testing.T) {
+	want, data := truncatedFile(t, 10)
+
+	for name, decode := range decoders(DecodeOptions{Salvage: true}) {
+		d, err := decode(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		placeholder := d.Directions[0].Frames[2]
+		if placeholder.Err() == nil || placeholder.Width != 0 || placeholder.Height != 0 {
+			t.Errorf("%s: expected an empty placeholder frame, got %dx%d, %v",
+				name, placeholder.Width, placeholder.Height, placeholder.Err())
+		}
+
+		for frameIdx := 0; frameIdx < 2; frameIdx++ {
+			if err := d.Directions[0].Frames[frameIdx].Err(); err != nil {
+				t.Errorf("%s: frame %d: %v", name, frameIdx, err)
+			}
+		}
+
+		for _, diff := range d.Diff(want) {
+			if diff.Frame != 2 {
+				t.Errorf("%s: unexpected difference %v", name, diff)
+			}
+		}
+	}
+}
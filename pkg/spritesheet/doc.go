@@ -0,0 +1,4 @@
+// Package spritesheet slices sprite sheets into frame images and imports them
+// as a DC6, either by a uniform grid or by a TexturePacker or Aseprite JSON
+// frame map.
+package spritesheet
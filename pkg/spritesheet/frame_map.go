@@ -0,0 +1,301 @@
+package spritesheet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+
+	dc6 "github.com/gravestench/dc6/pkg"
+)
+
+// FrameMap describes the frames of a sprite sheet, as exported by TexturePacker
+// or Aseprite in their JSON hash or JSON array format.
+type FrameMap struct {
+	Frames []MapFrame // in the order of the JSON file
+	Tags   []Tag      // Aseprite frame tags
+}
+
+// MapFrame is a frame of a FrameMap.
+type MapFrame struct {
+	Name string
+
+	// Rect is the location of the frame in the sheet.
+	Rect image.Rectangle
+
+	// Offset is the location of the top-left corner of the frame, relative to
+	// the pivot of the frame. Without a pivot, the offset is relative to the
+	// top-left corner of the untrimmed source image.
+	Offset image.Point
+}
+
+// Tag is an Aseprite frame tag, a range of frames.
+type Tag struct {
+	Name     string
+	From, To int // indices of the first and last frame of the tag
+}
+
+type jsonRect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type jsonFrame struct {
+	Filename         string    `json:"filename"`
+	Frame            jsonRect  `json:"frame"`
+	Rotated          bool      `json:"rotated"`
+	SpriteSourceSize *jsonRect `json:"spriteSourceSize"`
+	SourceSize       *jsonRect `json:"sourceSize"`
+	Pivot            *struct {
+		X float64 `json:"x"`
+		Y float64 `json:"y"`
+	} `json:"pivot"`
+}
+
+type jsonFrameMap struct {
+	Frames json.RawMessage `json:"frames"`
+	Meta   struct {
+		FrameTags []struct {
+			Name string `json:"name"`
+			From int    `json:"from"`
+			To   int    `json:"to"`
+		} `json:"frameTags"`
+	} `json:"meta"`
+}
+
+// ReadFrameMap reads a TexturePacker or Aseprite JSON frame map. Frames that
+// are rotated in the sheet are not supported.
+func ReadFrameMap(r io.Reader) (*FrameMap, error) {
+	var data jsonFrameMap
+
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("could not read frame map, %w", err)
+	}
+
+	frames, err := decodeFrames(data.Frames)
+	if err != nil {
+		return nil, fmt.Errorf("could not read frame map, %w", err)
+	}
+
+	m := &FrameMap{}
+
+	for idx := range frames {
+		frame, err := frames[idx].mapFrame()
+		if err != nil {
+			return nil, fmt.Errorf("could not read frame %q, %w", frames[idx].Filename, err)
+		}
+
+		m.Frames = append(m.Frames, frame)
+	}
+
+	for _, tag := range data.Meta.FrameTags {
+		m.Tags = append(m.Tags, Tag{Name: tag.Name, From: tag.From, To: tag.To})
+	}
+
+	return m, nil
+}
+
+// decodeFrames decodes the frames of the JSON array format, or those of the
+// JSON hash format in the order they appear in.
+func decodeFrames(data json.RawMessage) ([]jsonFrame, error) {
+	var frames []jsonFrame
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("no frames")
+	}
+
+	if data[0] == '[' {
+		err := json.Unmarshal(data, &frames)
+		return frames, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		frame := jsonFrame{}
+		if err := decoder.Decode(&frame); err != nil {
+			return nil, err
+		}
+
+		if name, ok := token.(string); ok && frame.Filename == "" {
+			frame.Filename = name
+		}
+
+		frames = append(frames, frame)
+	}
+
+	return frames, nil
+}
+
+func (f *jsonFrame) mapFrame() (MapFrame, error) {
+	if f.Rotated {
+		return MapFrame{}, errors.New("rotated frames are not supported")
+	}
+
+	frame := MapFrame{
+		Name: f.Filename,
+		Rect: image.Rect(f.Frame.X, f.Frame.Y, f.Frame.X+f.Frame.W, f.Frame.Y+f.Frame.H),
+	}
+
+	// trimmed frames are offset within their source image
+	if f.SpriteSourceSize != nil {
+		frame.Offset = image.Pt(f.SpriteSourceSize.X, f.SpriteSourceSize.Y)
+	}
+
+	if f.Pivot != nil {
+		sourceW, sourceH := f.Frame.W, f.Frame.H
+		if f.SourceSize != nil {
+			sourceW, sourceH = f.SourceSize.W, f.SourceSize.H
+		}
+
+		frame.Offset = frame.Offset.Sub(image.Pt(
+			int(math.Round(f.Pivot.X*float64(sourceW))),
+			int(math.Round(f.Pivot.Y*float64(sourceH))),
+		))
+	}
+
+	return frame, nil
+}
+
+// directionFramePattern matches frame names like the png files written by
+// dc6-convert, for example "sprite_d1_f4.png".
+var directionFramePattern = regexp.MustCompile(`_d(\d+)_f(\d+)(\.[^.]*)?$`)
+
+// Layout returns the indices into Frames of the frames of each direction.
+// With frame tags, each tag is a direction. Otherwise, if all frame names end
+// in _d<direction>_f<frame> (with an optional extension), the names are used.
+// Otherwise all frames are in a single direction.
+func (m *FrameMap) Layout() ([][]int, error) {
+	if len(m.Tags) > 0 {
+		return m.tagLayout()
+	}
+
+	layout, ok, err := m.nameLayout()
+	if ok || err != nil {
+		return layout, err
+	}
+
+	frames := make([]int, len(m.Frames))
+	for idx := range frames {
+		frames[idx] = idx
+	}
+
+	return [][]int{frames}, nil
+}
+
+func (m *FrameMap) tagLayout() ([][]int, error) {
+	layout := make([][]int, len(m.Tags))
+
+	for dirIdx, tag := range m.Tags {
+		if tag.From < 0 || tag.To >= len(m.Frames) || tag.From > tag.To {
+			return nil, fmt.Errorf("tag %q has invalid frame range %d-%d", tag.Name, tag.From, tag.To)
+		}
+
+		for idx := tag.From; idx <= tag.To; idx++ {
+			layout[dirIdx] = append(layout[dirIdx], idx)
+		}
+	}
+
+	return layout, nil
+}
+
+func (m *FrameMap) nameLayout() (layout [][]int, ok bool, err error) {
+	positions := make(map[[2]int]int)
+	directions, frames := 0, 0
+
+	for idx := range m.Frames {
+		match := directionFramePattern.FindStringSubmatch(m.Frames[idx].Name)
+		if match == nil {
+			return nil, false, nil
+		}
+
+		dirIdx, _ := strconv.Atoi(match[1])
+		frameIdx, _ := strconv.Atoi(match[2])
+
+		if _, found := positions[[2]int{dirIdx, frameIdx}]; found {
+			return nil, true, fmt.Errorf("direction %d frame %d appears more than once", dirIdx, frameIdx)
+		}
+
+		positions[[2]int{dirIdx, frameIdx}] = idx
+
+		if dirIdx >= directions {
+			directions = dirIdx + 1
+		}
+
+		if frameIdx >= frames {
+			frames = frameIdx + 1
+		}
+	}
+
+	if len(positions) != directions*frames {
+		const fmtErr = "frame names cover %d of %d directions with %d frames"
+		return nil, true, fmt.Errorf(fmtErr, len(positions), directions, frames)
+	}
+
+	layout = make([][]int, directions)
+
+	for dirIdx := range layout {
+		layout[dirIdx] = make([]int, frames)
+
+		for frameIdx := range layout[dirIdx] {
+			layout[dirIdx][frameIdx] = positions[[2]int{dirIdx, frameIdx}]
+		}
+	}
+
+	return layout, true, nil
+}
+
+// Slice returns the images of the frames of the sheet, indexed by direction
+// and frame as given by Layout. The bounds of each image are the bounds of its
+// frame.
+func (m *FrameMap) Slice(sheet image.Image) ([][]image.Image, error) {
+	layout, err := m.Layout()
+	if err != nil {
+		return nil, fmt.Errorf("could not slice sheet, %w", err)
+	}
+
+	images := make([][]image.Image, len(layout))
+
+	for dirIdx := range layout {
+		for frameIdx, idx := range layout[dirIdx] {
+			frame := m.Frames[idx]
+
+			img, err := frameImage(sheet, frame.Rect, frame.Offset)
+			if err != nil {
+				return nil, fmt.Errorf("could not slice direction %d frame %d, %w", dirIdx, frameIdx, err)
+			}
+
+			images[dirIdx] = append(images[dirIdx], img)
+		}
+	}
+
+	return images, nil
+}
+
+// Import slices the sheet and imports the frames as a DC6, see
+// dc6.ImportOptions.
+func (m *FrameMap) Import(sheet image.Image, o dc6.ImportOptions) (*dc6.DC6, error) {
+	images, err := m.Slice(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.Import(images)
+}
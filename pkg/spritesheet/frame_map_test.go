@@ -0,0 +1,191 @@
+package spritesheet
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	dc6 "github.com/gravestench/dc6/pkg"
+)
+
+// testSheet returns an opaque sprite sheet of the given size.
+func testSheet(width, height int) *image.NRGBA {
+	sheet := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			sheet.Set(x, y, color.NRGBA{R: 0xff, A: 0xff})
+		}
+	}
+
+	return sheet
+}
+
+// frameOffsets returns the sizes and offsets of the frames of the given DC6,
+// indexed by direction and frame.
+func frameOffsets(d *dc6.DC6) [][]image.Rectangle {
+	rects := make([][]image.Rectangle, len(d.Directions))
+
+	for dirIdx, direction := range d.Directions {
+		for _, frame := range direction.Frames {
+			rects[dirIdx] = append(rects[dirIdx], frame.Bounds())
+		}
+	}
+
+	return rects
+}
+
+const texturePackerHash = `{
+	"frames": {
+		"walk_d0_f1.png": {
+			"frame": {"x": 10, "y": 0, "w": 8, "h": 12},
+			"rotated": false,
+			"trimmed": true,
+			"spriteSourceSize": {"x": 3, "y": 4, "w": 8, "h": 12},
+			"sourceSize": {"w": 16, "h": 20},
+			"pivot": {"x": 0.5, "y": 1}
+		},
+		"walk_d0_f0.png": {
+			"frame": {"x": 0, "y": 0, "w": 10, "h": 16},
+			"rotated": false,
+			"trimmed": true,
+			"spriteSourceSize": {"x": 1, "y": 2, "w": 10, "h": 16},
+			"sourceSize": {"w": 16, "h": 20},
+			"pivot": {"x": 0.5, "y": 1}
+		}
+	},
+	"meta": {"image": "walk.png"}
+}`
+
+const texturePackerArray = `{
+	"frames": [
+		{
+			"filename": "idle 0",
+			"frame": {"x": 0, "y": 0, "w": 4, "h": 6},
+			"spriteSourceSize": {"x": 2, "y": 3, "w": 4, "h": 6},
+			"sourceSize": {"w": 8, "h": 9}
+		},
+		{
+			"filename": "idle 1",
+			"frame": {"x": 4, "y": 0, "w": 8, "h": 9}
+		}
+	]
+}`
+
+const asepriteTags = `{
+	"frames": [
+		{"filename": "run 0", "frame": {"x": 0, "y": 0, "w": 4, "h": 4}, "spriteSourceSize": {"x": 1, "y": 0, "w": 4, "h": 4}},
+		{"filename": "run 1", "frame": {"x": 4, "y": 0, "w": 4, "h": 4}, "spriteSourceSize": {"x": 2, "y": 0, "w": 4, "h": 4}},
+		{"filename": "run 2", "frame": {"x": 8, "y": 0, "w": 4, "h": 4}, "spriteSourceSize": {"x": 0, "y": 1, "w": 4, "h": 4}},
+		{"filename": "run 3", "frame": {"x": 12, "y": 0, "w": 4, "h": 4}, "spriteSourceSize": {"x": 0, "y": 2, "w": 4, "h": 4}}
+	],
+	"meta": {
+		"frameTags": [
+			{"name": "south", "from": 0, "to": 1, "direction": "forward"},
+			{"name": "west", "from": 2, "to": 3, "direction": "forward"}
+		]
+	}
+}`
+
+func TestFrameMapImport(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		names []string // of the frames in file order
+		want  [][]image.Rectangle
+	}{
+		{
+			name:  "texturepacker hash",
+			json:  texturePackerHash,
+			names: []string{"walk_d0_f1.png", "walk_d0_f0.png"},
+			want: [][]image.Rectangle{{
+				// the pivot is at (8, 20) in the source image
+				image.Rect(-7, -18, 3, -2),
+				image.Rect(-5, -16, 3, -4),
+			}},
+		},
+		{
+			name:  "texturepacker array",
+			json:  texturePackerArray,
+			names: []string{"idle 0", "idle 1"},
+			want: [][]image.Rectangle{{
+				image.Rect(2, 3, 6, 9),
+				image.Rect(0, 0, 8, 9),
+			}},
+		},
+		{
+			name:  "aseprite tags",
+			json:  asepriteTags,
+			names: []string{"run 0", "run 1", "run 2", "run 3"},
+			want: [][]image.Rectangle{
+				{image.Rect(1, 0, 5, 4), image.Rect(2, 0, 6, 4)},
+				{image.Rect(0, 1, 4, 5), image.Rect(0, 2, 4, 6)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		m, err := ReadFrameMap(strings.NewReader(test.json))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if len(m.Frames) != len(test.names) {
+			t.Fatalf("%s: expected %d frames, got %d", test.name, len(test.names), len(m.Frames))
+		}
+
+		for idx, name := range test.names {
+			if m.Frames[idx].Name != name {
+				t.Errorf("%s: expected frame %d to be named %q, got %q", test.name, idx, name, m.Frames[idx].Name)
+			}
+		}
+
+		d, err := m.Import(testSheet(32, 32), dc6.ImportOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		got := frameOffsets(d)
+
+		if len(got) != len(test.want) {
+			t.Fatalf("%s: expected %d directions, got %d", test.name, len(test.want), len(got))
+		}
+
+		for dirIdx := range test.want {
+			for frameIdx, want := range test.want[dirIdx] {
+				if got[dirIdx][frameIdx] != want {
+					t.Errorf("%s: direction %d frame %d: expected %v, got %v", test.name, dirIdx, frameIdx, want, got[dirIdx][frameIdx])
+				}
+			}
+		}
+	}
+}
+
+func TestFrameMapErrors(t *testing.T) {
+	tests := map[string]string{
+		"no frames":     `{"meta": {}}`,
+		"rotated frame": `{"frames": [{"filename": "a", "frame": {"x": 0, "y": 0, "w": 1, "h": 1}, "rotated": true}]}`,
+	}
+
+	for name, json := range tests {
+		if _, err := ReadFrameMap(strings.NewReader(json)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	duplicate := &FrameMap{Frames: []MapFrame{{Name: "a_d0_f0.png"}, {Name: "b_d0_f0.png"}}}
+	if _, err := duplicate.Layout(); err == nil {
+		t.Error("expected an error for duplicate frame names")
+	}
+
+	incomplete := &FrameMap{Frames: []MapFrame{{Name: "a_d0_f0.png"}, {Name: "a_d1_f1.png"}}}
+	if _, err := incomplete.Layout(); err == nil {
+		t.Error("expected an error for missing frames")
+	}
+
+	badTag := &FrameMap{Frames: []MapFrame{{Name: "a"}}, Tags: []Tag{{Name: "t", From: 0, To: 1}}}
+	if _, err := badTag.Layout(); err == nil {
+		t.Error("expected an error for a tag beyond the last frame")
+	}
+}
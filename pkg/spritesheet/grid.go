@@ -0,0 +1,78 @@
+package spritesheet
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	dc6 "github.com/gravestench/dc6/pkg"
+)
+
+// Grid slices a sprite sheet into cells of the same size, directions are the
+// rows and frames are the columns of the grid.
+type Grid struct {
+	CellWidth, CellHeight int
+
+	// Directions and Frames are the number of rows and columns of the grid,
+	// if 0, as many as fit in the sheet.
+	Directions, Frames int
+
+	// Pivot is the location within each cell the frame offsets are relative
+	// to, for example the feet of a character.
+	Pivot image.Point
+}
+
+// Slice returns the images of the cells of the sheet, indexed by direction
+// and frame. The bounds of each image are the bounds of its frame.
+func (g Grid) Slice(sheet image.Image) ([][]image.Image, error) {
+	if g.CellWidth <= 0 || g.CellHeight <= 0 {
+		return nil, fmt.Errorf("could not slice sheet, invalid cell size %dx%d", g.CellWidth, g.CellHeight)
+	}
+
+	bounds := sheet.Bounds()
+
+	directions, frames := g.Directions, g.Frames
+	if directions == 0 {
+		directions = bounds.Dy() / g.CellHeight
+	}
+
+	if frames == 0 {
+		frames = bounds.Dx() / g.CellWidth
+	}
+
+	if directions <= 0 || frames <= 0 {
+		return nil, errors.New("could not slice sheet, no cells fit in the sheet")
+	}
+
+	images := make([][]image.Image, directions)
+
+	for dirIdx := range images {
+		images[dirIdx] = make([]image.Image, frames)
+
+		for frameIdx := range images[dirIdx] {
+			cell := image.Rect(0, 0, g.CellWidth, g.CellHeight).
+				Add(bounds.Min).
+				Add(image.Pt(frameIdx*g.CellWidth, dirIdx*g.CellHeight))
+
+			img, err := frameImage(sheet, cell, g.Pivot.Mul(-1))
+			if err != nil {
+				return nil, fmt.Errorf("could not slice direction %d frame %d, %w", dirIdx, frameIdx, err)
+			}
+
+			images[dirIdx][frameIdx] = img
+		}
+	}
+
+	return images, nil
+}
+
+// Import slices the sheet and imports the cells as a DC6, see
+// dc6.ImportOptions.
+func (g Grid) Import(sheet image.Image, o dc6.ImportOptions) (*dc6.DC6, error) {
+	images, err := g.Slice(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.Import(images)
+}
@@ -0,0 +1,34 @@
+package spritesheet
+
+import (
+	"image"
+	"testing"
+
+	dc6 "github.com/gravestench/dc6/pkg"
+)
+
+func TestGridPivot(t *testing.T) {
+	g := Grid{CellWidth: 8, CellHeight: 10, Pivot: image.Pt(4, 9)}
+
+	d, err := g.Import(testSheet(16, 30), dc6.ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := frameOffsets(d)
+
+	if len(got) != 3 || len(got[0]) != 2 {
+		t.Fatalf("expected 3 directions of 2 frames, got %d of %d", len(got), len(got[0]))
+	}
+
+	// every cell is offset by the pivot, no matter where it is in the sheet
+	want := image.Rect(-4, -9, 4, 1)
+
+	for dirIdx := range got {
+		for frameIdx := range got[dirIdx] {
+			if got[dirIdx][frameIdx] != want {
+				t.Errorf("direction %d frame %d: expected %v, got %v", dirIdx, frameIdx, want, got[dirIdx][frameIdx])
+			}
+		}
+	}
+}
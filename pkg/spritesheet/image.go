@@ -0,0 +1,50 @@
+package spritesheet
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// offsetImage is an image whose bounds are moved by delta, so that the frame
+// offsets of an imported DC6 follow from the bounds of the frame images.
+type offsetImage struct {
+	image.Image
+	delta image.Point
+}
+
+func (i offsetImage) Bounds() image.Rectangle {
+	return i.Image.Bounds().Add(i.delta)
+}
+
+func (i offsetImage) At(x, y int) color.Color {
+	return i.Image.At(x-i.delta.X, y-i.delta.Y)
+}
+
+// croppedImage is a part of an image that does not implement SubImage.
+type croppedImage struct {
+	image.Image
+	rect image.Rectangle
+}
+
+func (i croppedImage) Bounds() image.Rectangle {
+	return i.rect
+}
+
+// frameImage returns the part of the sheet within rect, with its top-left
+// corner moved to offset.
+func frameImage(sheet image.Image, rect image.Rectangle, offset image.Point) (image.Image, error) {
+	if !rect.In(sheet.Bounds()) {
+		return nil, fmt.Errorf("frame %v is outside of the sheet %v", rect, sheet.Bounds())
+	}
+
+	var sub image.Image = croppedImage{Image: sheet, rect: rect}
+
+	if s, ok := sheet.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		sub = s.SubImage(rect)
+	}
+
+	return offsetImage{Image: sub, delta: offset.Sub(rect.Min)}, nil
+}
@@ -0,0 +1,121 @@
+package pkg
+
+import "fmt"
+
+// WarningKind identifies the kind of inconsistency a Warning describes.
+type WarningKind int
+
+const (
+	// WarningNextBlockMismatch means a frame's NextBlock does not point at the
+	// frame block that follows it in the file (or, for the last block, at the
+	// end of the block).
+	WarningNextBlockMismatch WarningKind = iota
+
+	// WarningBadTermination means the termination bytes of the file header, or
+	// the terminator of a frame, are not all 0xEE.
+	WarningBadTermination
+
+	// WarningTrailingData means there is data after the last scanline of a
+	// frame, or after the last frame of the file.
+	WarningTrailingData
+
+	// WarningScanlineOverrun means a run of opaque pixels extends beyond the
+	// width of the frame, the pixels beyond the width are clipped.
+	WarningScanlineOverrun
+
+	// WarningBadVersion means the file header has a version other than 6.
+	WarningBadVersion
+
+	// WarningUnterminatedScanline means the frame data ends before all of the
+	// scanlines of the frame are terminated, the missing pixels are
+	// transparent.
+	WarningUnterminatedScanline
+)
+
+// Warning describes an inconsistency that was found while decoding a DC6,
+// but which did not prevent the DC6 from being decoded.
+type Warning struct {
+	Kind      WarningKind
+	Direction int   // -1 if the warning is not about a specific frame
+	Frame     int   // -1 if the warning is not about a specific frame
+	Offset    int64 // the file offset the warning relates to
+	Message   string
+}
+
+func (w Warning) String() string {
+	if w.Direction < 0 {
+		return fmt.Sprintf("offset %d: %s", w.Offset, w.Message)
+	}
+
+	return fmt.Sprintf("direction %d frame %d, offset %d: %s", w.Direction, w.Frame, w.Offset, w.Message)
+}
+
+// Warnings returns the inconsistencies that were found while decoding the DC6,
+// followed by those of each frame. Frames that are decoded lazily only report
+// warnings about their frame data once they have been decoded.
+func (d *DC6) Warnings() []Warning {
+	warnings := append([]Warning(nil), d.warnings...)
+
+	for dirIdx := range d.Directions {
+		for _, frame := range d.Directions[dirIdx].Frames {
+			if frame != nil {
+				warnings = append(warnings, frame.Warnings()...)
+			}
+		}
+	}
+
+	return warnings
+}
+
+// Warnings returns the inconsistencies that were found while decoding the frame.
+func (f *Frame) Warnings() []Warning {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Warning(nil), f.warnings...)
+}
+
+// anomaly handles an inconsistency in the file header according to the decode
+// mode, it is either recorded as a warning or returned as an error.
+func (d *DC6) anomaly(kind WarningKind, offset int64, err error) error {
+	if d.mode.rejects(kind) {
+		return headerError(offset, err)
+	}
+
+	d.warnings = append(d.warnings, Warning{
+		Kind:      kind,
+		Direction: -1,
+		Frame:     -1,
+		Offset:    offset,
+		Message:   err.Error(),
+	})
+
+	return nil
+}
+
+// anomaly handles an inconsistency in a frame according to the decode mode,
+// it is either recorded as a warning or returned as an error.
+func (f *Frame) anomaly(kind WarningKind, offset int64, err error) error {
+	if f.mode().rejects(kind) {
+		return &DecodeError{Direction: f.direction, Frame: f.index, Offset: offset, Err: err}
+	}
+
+	f.warnings = append(f.warnings, Warning{
+		Kind:      kind,
+		Direction: f.direction,
+		Frame:     f.index,
+		Offset:    offset,
+		Message:   err.Error(),
+	})
+
+	return nil
+}
+
+// mode returns the decode mode of the DC6 the frame belongs to.
+func (f *Frame) mode() DecodeMode {
+	if f.dc6 == nil {
+		return ModeDefault
+	}
+
+	return f.dc6.mode
+}
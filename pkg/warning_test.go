@@ -0,0 +1,174 @@
+package pkg
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestNextBlockFileOrder(t *testing.T) {
+	want := newTestDC6(2, 3)
+
+	data, err := want.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data = relayout(data, 5)
+	for name, decode := range decoders(DecodeOptions{Mode: ModeStrict}) {
+		got, err := decode(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if warnings := got.Warnings(); len(warnings) > 0 {
+			t.Errorf("%s: unexpected warnings %v", name, warnings)
+		}
+
+		if !got.Equal(want) {
+			t.Errorf("%s: decoded DC6 differs: %v", name, got.Diff(want))
+		}
+	}
+}
+
+func TestNextBlockMismatch(t *testing.T) {
+	data, err := newTestDC6(1, 2).ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pointer := binary.LittleEndian.Uint32(data[headerSize:])
+	binary.LittleEndian.PutUint32(data[pointer+24:], 1)
+
+	d, err := FromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if warnings := d.Warnings(); len(warnings) != 1 || warnings[0].Kind != WarningNextBlockMismatch {
+		t.Errorf("expected a next block mismatch, got %v", warnings)
+	}
+
+	if _, err := (DecodeOptions{Mode: ModeStrict}).FromBytes(data); !errors.Is(err, ErrNextBlockMismatch) {
+		t.Errorf("expected ErrNextBlockMismatch, got %v", err)
+	}
+}
+
+func TestTrailingDataAfterLastFrame(t *testing.T) {
+	data, err := newTestDC6(1, 2).ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data = append(data, 1, 2, 3)
+
+	for name, decode := range decoders(DecodeOptions{Mode: ModeStrict}) {
+		if _, err := decode(data); !errors.Is(err, ErrTrailingData) {
+			t.Errorf("%s: expected ErrTrailingData, got %v", name, err)
+		}
+	}
+
+	for name, decode := range decoders(DecodeOptions{}) {
+		d, err := decode(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if warnings := d.Warnings(); len(warnings) != 1 || warnings[0].Kind != WarningTrailingData {
+			t.Errorf("%s: expected trailing data, got %v", name, warnings)
+		}
+	}
+}
+
+// decodeAll decodes the given file and all of its frames.
+func decodeAll(decode func(data []byte) (*DC6, error), data []byte) (*DC6, error) {
+	d, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, direction := range d.Directions {
+		for _, frame := range direction.Frames {
+			if err := frame.Decode(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return d, nil
+}
+
+func TestDecodeModes(t *testing.T) {
+	d := New(1, 1)
+	d.Directions[0].AddFrame(&Frame{
+		Width:     5,
+		Height:    1,
+		IndexData: []byte{1, 2, 3, 4, 5},
+		Mask:      []bool{true, true, true, true, true},
+	})
+
+	data, err := d.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pointer := binary.LittleEndian.Uint32(data[headerSize:])
+
+	tests := []struct {
+		name   string
+		modify func(data []byte) []byte
+		kind   WarningKind
+		err    error
+	}{
+		{"bad version", func(data []byte) []byte {
+			data[0] = 5
+			return data
+		}, WarningBadVersion, ErrBadVersion},
+		{"header termination", func(data []byte) []byte {
+			data[12] = 0 // the first termination byte
+			return data
+		}, WarningBadTermination, ErrBadTerminator},
+		{"frame terminator", func(data []byte) []byte {
+			data[len(data)-1] = 0
+			return data
+		}, WarningBadTermination, ErrBadTerminator},
+		{"scanline overrun", func(data []byte) []byte {
+			binary.LittleEndian.PutUint32(data[pointer+4:], 4)
+			return data
+		}, WarningScanlineOverrun, ErrScanlineOverrun},
+		{"unterminated scanline", func(data []byte) []byte {
+			binary.LittleEndian.PutUint32(data[pointer+8:], 2)
+			return data
+		}, WarningUnterminatedScanline, ErrBadTerminator},
+		{"frame trailing data", func(data []byte) []byte {
+			binary.LittleEndian.PutUint32(data[pointer+8:], 0)
+			return data
+		}, WarningTrailingData, ErrTrailingData},
+		{"file trailing data", func(data []byte) []byte {
+			return append(data, 0xee)
+		}, WarningTrailingData, ErrTrailingData},
+	}
+
+	for _, test := range tests {
+		file := test.modify(append([]byte(nil), data...))
+
+		for name, decode := range decoders(DecodeOptions{Mode: ModeStrict}) {
+			if _, err := decodeAll(decode, file); !errors.Is(err, test.err) {
+				t.Errorf("%s, strict %s: expected %v, got %v", test.name, name, test.err, err)
+			}
+		}
+
+		for _, mode := range []DecodeMode{ModeDefault, ModeLenient} {
+			for name, decode := range decoders(DecodeOptions{Mode: mode}) {
+				got, err := decodeAll(decode, file)
+				if err != nil {
+					t.Fatalf("%s, mode %d %s: %v", test.name, mode, name, err)
+				}
+
+				if warnings := got.Warnings(); len(warnings) != 1 || warnings[0].Kind != test.kind {
+					t.Errorf("%s, mode %d %s: expected a single warning of kind %d, got %v", test.name, mode, name, test.kind, warnings)
+				}
+			}
+		}
+	}
+}